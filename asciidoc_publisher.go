@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// asciidocPublisher writes page per table and index.adoc that includes all pages as a book.
+type asciidocPublisher struct {
+	basePublisher
+}
+
+func newAsciiDocPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *asciidocPublisher {
+	return &asciidocPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *asciidocPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	anchors := asciidocAnchors(tables)
+	for _, tbl := range tables {
+		p.write(asciidocPage(tbl.Name()), convertToAsciiDoc(tbl, anchors, p.conv, p.loc))
+	}
+	p.write("index.adoc", convertToIndexAsciiDoc(tables, p.loc))
+}
+
+// convertToAsciiDoc makes same sections as convertToMarkdown.
+// Tables of foreign keys and referenced keys are cross-referenced when they are in anchors.
+func convertToAsciiDoc(table *dbmodel.Table, anchors map[string]string, conv Converter, loc locale) []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "[[%s]]\n", asciidocID(table.Name()))
+	fmt.Fprintln(buf, "==", table.Name())
+	if table.Comment() != "" {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, table.Comment())
+	}
+
+	rows := make([][]string, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		rows = append(rows, conv.ConvertColumn(col))
+	}
+	writeAsciiDocTable(buf, loc.t("column", "title"), translateHeaders(loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"), rows, nil)
+
+	if len(table.Indices()) > 0 {
+		rows = make([][]string, 0, len(table.Indices()))
+		for _, idx := range table.Indices() {
+			rows = append(rows, conv.ConvertIndex(idx))
+		}
+		writeAsciiDocTable(buf, loc.t("index", "title"), translateHeaders(loc, "index", "name", "columns", "unique"), rows, nil)
+	}
+
+	if len(table.Constraints()) > 0 {
+		rows = make([][]string, 0, len(table.Constraints()))
+		for _, con := range table.Constraints() {
+			rows = append(rows, conv.ConvertConstraint(con))
+		}
+		writeAsciiDocTable(buf, loc.t("constraint", "title"), translateHeaders(loc, "constraint", "name", "kind", "content"), rows, nil)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ForeignKeys()))
+		xrefs := make([]map[int]string, 0, len(table.ForeignKeys()))
+		for _, fk := range table.ForeignKeys() {
+			rows = append(rows, conv.ConvertForeignKey(fk))
+			xref := make(map[int]string)
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				xref[2] = anchors[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]
+			}
+			xrefs = append(xrefs, xref)
+		}
+		writeAsciiDocTable(buf, loc.t("foreign_key", "title"), translateHeaders(loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"), rows, xrefs)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ReferencedKeys()))
+		xrefs := make([]map[int]string, 0, len(table.ReferencedKeys()))
+		for _, rk := range table.ReferencedKeys() {
+			rows = append(rows, conv.ConvertReferencedKey(rk))
+			xrefs = append(xrefs, map[int]string{1: anchors[ddlKey(rk.Schema(), rk.TableName())]})
+		}
+		writeAsciiDocTable(buf, loc.t("referenced_key", "title"), translateHeaders(loc, "referenced_key", "name", "source_table", "source_columns", "columns"), rows, xrefs)
+	}
+
+	return buf.Bytes()
+}
+
+// convertToIndexAsciiDoc makes document that has table list and includes all table pages.
+func convertToIndexAsciiDoc(tables []*dbmodel.Table, loc locale) []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "=", loc.t("table_list", "title"))
+	fmt.Fprintln(buf, ":toc:")
+	fmt.Fprintln(buf, ":toclevels: 1")
+
+	rows := make([][]string, 0, len(tables))
+	xrefs := make([]map[int]string, 0, len(tables))
+	for _, tbl := range tables {
+		rows = append(rows, []string{tbl.Name(), tbl.Comment()})
+		xrefs = append(xrefs, map[int]string{0: asciidocID(tbl.Name())})
+	}
+	fmt.Fprintln(buf)
+	writeAsciiDocTableBody(buf, translateHeaders(loc, "table_list", "table", "comment"), rows, xrefs)
+
+	for _, tbl := range tables {
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "include::%s[]\n", asciidocPage(tbl.Name()))
+	}
+	return buf.Bytes()
+}
+
+// writeAsciiDocTable writes section that has a table. Cell is written as cross reference when xrefs has anchor of it.
+func writeAsciiDocTable(buf *bytes.Buffer, title string, headers []string, rows [][]string, xrefs []map[int]string) {
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "===", title)
+	fmt.Fprintln(buf)
+	writeAsciiDocTableBody(buf, headers, rows, xrefs)
+}
+
+func writeAsciiDocTableBody(buf *bytes.Buffer, headers []string, rows [][]string, xrefs []map[int]string) {
+	fmt.Fprintln(buf, `[options="header"]`)
+	fmt.Fprintln(buf, "|===")
+	fmt.Fprintln(buf, asciidocRow(headers, nil))
+	for i, row := range rows {
+		var xref map[int]string
+		if xrefs != nil {
+			xref = xrefs[i]
+		}
+		fmt.Fprintln(buf, asciidocRow(row, xref))
+	}
+	fmt.Fprintln(buf, "|===")
+}
+
+func asciidocRow(values []string, xref map[int]string) string {
+	cells := make([]string, 0, len(values))
+	for i, v := range values {
+		if id := xref[i]; id != "" {
+			cells = append(cells, fmt.Sprintf("|<<%s,%s>>", id, asciidocEscape(v)))
+		} else {
+			cells = append(cells, "|"+asciidocEscape(v))
+		}
+	}
+	return strings.Join(cells, " ")
+}
+
+// asciidocPage returns path of table page.
+// Pages are put in tables directory so that table named index does not overwrite index.adoc.
+func asciidocPage(name string) string {
+	return "tables/" + name + ".adoc"
+}
+
+// asciidocAnchors returns anchor of tables. Key is made by ddlKey.
+func asciidocAnchors(tables []*dbmodel.Table) map[string]string {
+	anchors := make(map[string]string)
+	for _, tbl := range tables {
+		anchors[ddlKey(tbl.Schema(), tbl.Name())] = asciidocID(tbl.Name())
+	}
+	return anchors
+}
+
+// asciidocID returns table name as ID of anchor. Characters that are not allowed in ID are replaced with '_'.
+func asciidocID(name string) string {
+	return diagramAlias(name)
+}
+
+// asciidocEscape escapes cell separator, and line break is kept by hard line break.
+func asciidocEscape(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", " +\n", -1)
+}
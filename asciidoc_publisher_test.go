@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+func TestConvertToAsciiDoc(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id serial PRIMARY KEY, name varchar(50) NOT NULL DEFAULT 'a|b');
+COMMENT ON TABLE shops IS 'Shops';
+COMMENT ON COLUMN shops.name IS 'Name
+of shop';
+CREATE SCHEMA hr;
+CREATE TABLE hr.staffs (id int PRIMARY KEY);
+CREATE TABLE staffs (id serial PRIMARY KEY, shop_id int NOT NULL REFERENCES shops (id), staff_id int REFERENCES hr.staffs (id), CHECK (id > 0));
+CREATE INDEX staffs_shop_id_idx ON staffs (shop_id);
+`)
+	var shops, staffs = tables[0], tables[0]
+	for _, tbl := range tables {
+		if tbl.Schema() == "public" && tbl.Name() == "shops" {
+			shops = tbl
+		}
+		if tbl.Schema() == "public" && tbl.Name() == "staffs" {
+			staffs = tbl
+		}
+	}
+	anchors := asciidocAnchors([]*dbmodel.Table{shops, staffs})
+	conv := findConverter(true, "postgres")
+
+	expected := `[[shops]]
+== shops
+
+Shops
+
+=== Columns
+
+[options="header"]
+|===
+|PK |NAME |TYPE |SIZE |NULL |DEFAULT |COMMENT
+|1 |id |serial | |NO | |
+| |name |varchar |50 |NO |'a\|b' |Name +
+of shop
+|===
+
+=== Indices
+
+[options="header"]
+|===
+|NAME |COLUMNS |UNIQUE
+|shops_pkey |id |YES
+|===
+
+=== Referenced keys
+
+[options="header"]
+|===
+|NAME |SOURCE TABLE |SOURCE COLUMNS |COLUMNS
+|staffs_shop_id_fkey |<<staffs,staffs>> |shop_id |id
+|===
+`
+	if actual := string(convertToAsciiDoc(shops, anchors, conv, l("en"))); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	actual := string(convertToAsciiDoc(staffs, anchors, conv, l("en")))
+	for _, s := range []string{
+		"[[staffs]]\n== staffs\n\n=== Columns\n",
+		"\n=== Constraints\n",
+		"|staffs_shop_id_fkey |shop_id |<<shops,shops>> |id\n",
+		"|staffs_staff_id_fkey |staff_id |hr.staffs |id\n",
+	} {
+		if !strings.Contains(actual, s) {
+			t.Errorf("staffs.adoc should contain %s, actual:\n%s", s, actual)
+		}
+	}
+}
+
+func TestCmdPublishAsciiDoc(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "asciidoc"
+	publishOpt.locale = "ja"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/tables/posts.adoc
+Created: out/tables/users.adoc
+Created: out/index.adoc
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `= テーブル一覧
+:toc:
+:toclevels: 1
+
+[options="header"]
+|===
+|テーブル |コメント
+|<<posts,posts>> |Posts of users
+|<<users,users>> |
+|===
+
+include::tables/posts.adoc[]
+
+include::tables/users.adoc[]
+`
+	if actual := readPublished(t, dir, "index.adoc"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	if posts := readPublished(t, dir, "tables/posts.adoc"); !strings.Contains(posts, "|posts_user_id_fkey |user_id |<<users,users>> |id\n") {
+		t.Errorf("tables/posts.adoc should have cross reference to users, actual:\n%s", posts)
+	}
+}
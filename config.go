@@ -12,17 +12,21 @@ import (
 
 // Config stores loaded config file content
 type Config struct {
-	FilePath string            `json:"-"`
-	Driver   string            `json:"driver"`
-	Version  string            `json:"version"`
-	Host     string            `json:"host"`
-	Port     int               `json:"port"`
-	User     string            `json:"user"`
-	Password string            `json:"password"`
-	Database string            `json:"database"`
-	Schema   string            `json:"schema"`
-	Options  map[string]string `json:"options"`
-	Out      string            `json:"out"`
+	FilePath  string            `json:"-"`
+	Driver    string            `json:"driver"`
+	Version   string            `json:"version"`
+	Host      string            `json:"host"`
+	Port      int               `json:"port"`
+	User      string            `json:"user"`
+	Password  string            `json:"password"`
+	Database  string            `json:"database"`
+	Schema    string            `json:"schema"`
+	Options   map[string]string `json:"options"`
+	Out       string            `json:"out"`
+	Diagram   DiagramConfig     `json:"diagram"`
+	Markdown  MarkdownConfig    `json:"markdown"`
+	CSV       CSVConfig         `json:"csv"`
+	Templates TemplateConfig    `json:"templates"`
 }
 
 func loadConfig(path string) (*Config, error) {
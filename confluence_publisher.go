@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// confluencePublisher writes pages in Confluence storage format and manifest of page tree.
+// Pages are only written to files, and they are imported by other tools or by hand.
+// Table pages are written in tables directory so that page of table named index does not overwrite index.xml.
+type confluencePublisher struct {
+	basePublisher
+}
+
+// confluencePage is node of page tree. File is relative path of page in storage format.
+type confluencePage struct {
+	Title    string            `json:"title"`
+	File     string            `json:"file"`
+	Children []*confluencePage `json:"children,omitempty"`
+}
+
+func newConfluencePublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *confluencePublisher {
+	return &confluencePublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *confluencePublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	titles := make(map[string]string)
+	for _, tbl := range tables {
+		titles[ddlKey(tbl.Schema(), tbl.Name())] = tbl.Name()
+	}
+	root := &confluencePage{Title: p.loc.t("table_list", "title"), File: "index.xml", Children: make([]*confluencePage, 0, len(tables))}
+	for _, tbl := range tables {
+		page := &confluencePage{Title: tbl.Name(), File: "tables/" + tbl.Name() + ".xml"}
+		root.Children = append(root.Children, page)
+		p.write(page.File, convertToConfluence(tbl, titles, p.conv, p.loc))
+	}
+	p.write(root.File, convertToIndexConfluence(tables, p.loc))
+
+	b, err := marshalJSONDocument(root)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write("manifest.json", b)
+}
+
+// convertToConfluence makes same sections as convertToMarkdown.
+// Tables of foreign keys and referenced keys are linked by ac:link when they are in titles.
+func convertToConfluence(table *dbmodel.Table, titles map[string]string, conv Converter, loc locale) []byte {
+	buf := &bytes.Buffer{}
+	if table.Comment() != "" {
+		fmt.Fprintf(buf, "<p>%s</p>\n", confluenceText(table.Comment()))
+	}
+
+	rows := make([][]string, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		rows = append(rows, confluenceTextRow(conv.ConvertColumn(col)))
+	}
+	writeConfluenceTable(buf, loc.t("column", "title"), translateHeaders(loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"), rows)
+
+	if len(table.Indices()) > 0 {
+		rows = make([][]string, 0, len(table.Indices()))
+		for _, idx := range table.Indices() {
+			rows = append(rows, confluenceTextRow(conv.ConvertIndex(idx)))
+		}
+		writeConfluenceTable(buf, loc.t("index", "title"), translateHeaders(loc, "index", "name", "columns", "unique"), rows)
+	}
+
+	if len(table.Constraints()) > 0 {
+		rows = make([][]string, 0, len(table.Constraints()))
+		for _, con := range table.Constraints() {
+			rows = append(rows, confluenceTextRow(conv.ConvertConstraint(con)))
+		}
+		writeConfluenceTable(buf, loc.t("constraint", "title"), translateHeaders(loc, "constraint", "name", "kind", "content"), rows)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ForeignKeys()))
+		for _, fk := range table.ForeignKeys() {
+			values := conv.ConvertForeignKey(fk)
+			row := confluenceTextRow(values)
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				if title, ok := titles[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]; ok {
+					row[2] = confluenceLink(title, values[2])
+				}
+			}
+			rows = append(rows, row)
+		}
+		writeConfluenceTable(buf, loc.t("foreign_key", "title"), translateHeaders(loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"), rows)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ReferencedKeys()))
+		for _, rk := range table.ReferencedKeys() {
+			values := conv.ConvertReferencedKey(rk)
+			row := confluenceTextRow(values)
+			if title, ok := titles[ddlKey(rk.Schema(), rk.TableName())]; ok {
+				row[1] = confluenceLink(title, values[1])
+			}
+			rows = append(rows, row)
+		}
+		writeConfluenceTable(buf, loc.t("referenced_key", "title"), translateHeaders(loc, "referenced_key", "name", "source_table", "source_columns", "columns"), rows)
+	}
+
+	return buf.Bytes()
+}
+
+// convertToIndexConfluence makes index page that links to all table pages.
+func convertToIndexConfluence(tables []*dbmodel.Table, loc locale) []byte {
+	buf := &bytes.Buffer{}
+	rows := make([][]string, 0, len(tables))
+	for _, tbl := range tables {
+		rows = append(rows, []string{confluenceLink(tbl.Name(), tbl.Name()), confluenceText(tbl.Comment())})
+	}
+	writeConfluenceTable(buf, "", translateHeaders(loc, "table_list", "table", "comment"), rows)
+	return buf.Bytes()
+}
+
+// writeConfluenceTable writes heading and table. Values of rows must be escaped.
+func writeConfluenceTable(buf *bytes.Buffer, title string, headers []string, rows [][]string) {
+	if title != "" {
+		fmt.Fprintf(buf, "<h2>%s</h2>\n", confluenceText(title))
+	}
+	buf.WriteString("<table>\n<tbody>\n<tr>")
+	for _, h := range headers {
+		fmt.Fprintf(buf, "<th>%s</th>", confluenceText(h))
+	}
+	buf.WriteString("</tr>\n")
+	for _, row := range rows {
+		buf.WriteString("<tr>")
+		for _, v := range row {
+			fmt.Fprintf(buf, "<td>%s</td>", v)
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</tbody>\n</table>\n")
+}
+
+// confluenceLink returns link to page of title.
+func confluenceLink(title string, text string) string {
+	return fmt.Sprintf(`<ac:link><ri:page ri:content-title="%s" /><ac:plain-text-link-body><![CDATA[%s]]></ac:plain-text-link-body></ac:link>`,
+		xmlEscape(title), strings.Replace(text, "]]>", "]]]]><![CDATA[>", -1))
+}
+
+func confluenceTextRow(values []string) []string {
+	escaped := make([]string, 0, len(values))
+	for _, v := range values {
+		escaped = append(escaped, confluenceText(v))
+	}
+	return escaped
+}
+
+// confluenceText escapes text, and line break is written as br.
+func confluenceText(s string) string {
+	return strings.Replace(xmlEscape(s), "&#xA;", "<br />", -1)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConvertToConfluence(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id serial PRIMARY KEY, name varchar(50) NOT NULL DEFAULT '<none>');
+COMMENT ON TABLE shops IS 'Shops & stores
+in town';
+CREATE SCHEMA hr;
+CREATE TABLE hr.staffs (id int PRIMARY KEY);
+CREATE TABLE staffs (id serial PRIMARY KEY, shop_id int NOT NULL REFERENCES shops (id), staff_id int REFERENCES hr.staffs (id));
+`)
+	var shops, staffs = tables[0], tables[0]
+	for _, tbl := range tables {
+		if tbl.Schema() == "public" && tbl.Name() == "shops" {
+			shops = tbl
+		}
+		if tbl.Schema() == "public" && tbl.Name() == "staffs" {
+			staffs = tbl
+		}
+	}
+	titles := map[string]string{ddlKey("public", "shops"): "shops", ddlKey("public", "staffs"): "staffs"}
+	conv := findConverter(true, "postgres")
+
+	expected := `<p>Shops &amp; stores<br />in town</p>
+<h2>Columns</h2>
+<table>
+<tbody>
+<tr><th>PK</th><th>NAME</th><th>TYPE</th><th>SIZE</th><th>NULL</th><th>DEFAULT</th><th>COMMENT</th></tr>
+<tr><td>1</td><td>id</td><td>serial</td><td></td><td>NO</td><td></td><td></td></tr>
+<tr><td></td><td>name</td><td>varchar</td><td>50</td><td>NO</td><td>&#39;&lt;none&gt;&#39;</td><td></td></tr>
+</tbody>
+</table>
+<h2>Indices</h2>
+<table>
+<tbody>
+<tr><th>NAME</th><th>COLUMNS</th><th>UNIQUE</th></tr>
+<tr><td>shops_pkey</td><td>id</td><td>YES</td></tr>
+</tbody>
+</table>
+<h2>Referenced keys</h2>
+<table>
+<tbody>
+<tr><th>NAME</th><th>SOURCE TABLE</th><th>SOURCE COLUMNS</th><th>COLUMNS</th></tr>
+<tr><td>staffs_shop_id_fkey</td><td><ac:link><ri:page ri:content-title="staffs" /><ac:plain-text-link-body><![CDATA[staffs]]></ac:plain-text-link-body></ac:link></td><td>shop_id</td><td>id</td></tr>
+</tbody>
+</table>
+`
+	if actual := string(convertToConfluence(shops, titles, conv, l("en"))); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	actual := string(convertToConfluence(staffs, titles, conv, l("en")))
+	for _, s := range []string{
+		`<tr><td>staffs_shop_id_fkey</td><td>shop_id</td><td><ac:link><ri:page ri:content-title="shops" /><ac:plain-text-link-body><![CDATA[shops]]></ac:plain-text-link-body></ac:link></td><td>id</td></tr>`,
+		`<tr><td>staffs_staff_id_fkey</td><td>staff_id</td><td>hr.staffs</td><td>id</td></tr>`,
+	} {
+		if !strings.Contains(actual, s) {
+			t.Errorf("staffs.xml should contain %s, actual:\n%s", s, actual)
+		}
+	}
+}
+
+func TestConfluenceLink(t *testing.T) {
+	expected := `<ac:link><ri:page ri:content-title="a&#34;b" /><ac:plain-text-link-body><![CDATA[x]]]]><![CDATA[>y]]></ac:plain-text-link-body></ac:link>`
+	if actual := confluenceLink(`a"b`, "x]]>y"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdPublishConfluence(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "confluence"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/tables/posts.xml
+Created: out/tables/users.xml
+Created: out/index.xml
+Created: out/manifest.json
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	var root confluencePage
+	if err := json.Unmarshal([]byte(readPublished(t, dir, "manifest.json")), &root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Title != "Table index" || root.File != "index.xml" || len(root.Children) != 2 ||
+		root.Children[0].Title != "posts" || root.Children[0].File != "tables/posts.xml" || root.Children[1].Title != "users" {
+		t.Errorf("invalid manifest: %+v", root)
+	}
+	if idx := readPublished(t, dir, "index.xml"); !strings.Contains(idx, `<tr><td><ac:link><ri:page ri:content-title="posts" /><ac:plain-text-link-body><![CDATA[posts]]></ac:plain-text-link-body></ac:link></td><td>Posts of users</td></tr>`) {
+		t.Errorf("index.xml should link to posts, actual:\n%s", idx)
+	}
+	if posts := readPublished(t, dir, "tables/posts.xml"); !strings.Contains(posts, `<ri:page ri:content-title="users" />`) {
+		t.Errorf("tables/posts.xml should link to users, actual:\n%s", posts)
+	}
+}
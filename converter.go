@@ -25,6 +25,10 @@ func findConverter(pretty bool, driver string) Converter {
 		return postgresPrettyConverter{}
 	}
 
+	if driver == "mysql" {
+		return mysqlPrettyConverter{}
+	}
+
 	return defaultConverter{}
 }
 
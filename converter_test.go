@@ -21,6 +21,13 @@ func TestFindConverterWhenPrettyPostgres(t *testing.T) {
 	}
 }
 
+func TestFindConverterWhenPrettyMysql(t *testing.T) {
+	conv := findConverter(true, "mysql")
+	if _, ok := conv.(mysqlPrettyConverter); !ok {
+		t.Error("If pretty is true and driver is 'mysql', findConverter should return mysqlPrettyConverter.")
+	}
+}
+
 func TestFindConverterWhenUnknownDriver(t *testing.T) {
 	conv := findConverter(true, "unknown")
 	if _, ok := conv.(defaultConverter); !ok {
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// CSVConfig is setting of csv format. Delimiter is ',' by default, and 'tab' makes TSV.
+type CSVConfig struct {
+	Delimiter string `json:"delimiter"`
+}
+
+// csvPublisher writes data dictionary as flat files for spreadsheets and BI tools.
+type csvPublisher struct {
+	basePublisher
+}
+
+func newCSVPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *csvPublisher {
+	return &csvPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *csvPublisher) Publish(tables []*dbmodel.Table) {
+	comma, err := csvDelimiter(p.cfg.CSV.Delimiter)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	if err = p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	ext := ".csv"
+	if comma == '\t' {
+		ext = ".tsv"
+	}
+
+	tblRows := [][]string{translateHeaders(p.loc, "csv", "schema", "table", "comment")}
+	colRows := [][]string{translateHeaders(p.loc, "csv", "schema", "table", "table_comment", "ordinal", "column", "data_type", "size", "nullable", "default_value", "primary_key", "foreign_key", "comment")}
+	idxRows := [][]string{translateHeaders(p.loc, "csv", "schema", "table", "index", "columns", "unique")}
+	fkRows := [][]string{translateHeaders(p.loc, "csv", "schema", "table", "foreign_key_name", "columns", "foreign_table", "foreign_columns")}
+	for _, tbl := range tables {
+		tblRows = append(tblRows, []string{tbl.Schema(), tbl.Name(), tbl.Comment()})
+		for i, col := range tbl.Columns() {
+			values := p.conv.ConvertColumn(col)
+			nullable := "NO"
+			if col.IsNullable() {
+				nullable = "YES"
+			}
+			colRows = append(colRows, []string{tbl.Schema(), tbl.Name(), tbl.Comment(), strconv.Itoa(i + 1), col.Name(), values[2], values[3], nullable, values[5], values[0], csvForeignKeyTargets(tbl, col), col.Comment()})
+		}
+		for _, idx := range tbl.Indices() {
+			idxRows = append(idxRows, append([]string{tbl.Schema(), tbl.Name()}, p.conv.ConvertIndex(idx)...))
+		}
+		for _, fk := range tbl.ForeignKeys() {
+			fkRows = append(fkRows, append([]string{tbl.Schema(), tbl.Name()}, p.conv.ConvertForeignKey(fk)...))
+		}
+	}
+
+	p.writeCSV("tables"+ext, comma, tblRows)
+	p.writeCSV("columns"+ext, comma, colRows)
+	p.writeCSV("indices"+ext, comma, idxRows)
+	p.writeCSV("foreign_keys"+ext, comma, fkRows)
+}
+
+func (p *csvPublisher) writeCSV(name string, comma rune, rows [][]string) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	w.Comma = comma
+	if err := w.WriteAll(rows); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write(name, buf.Bytes())
+}
+
+// csvDelimiter returns delimiter rune. Empty is ',', and 'tab' or '\t' is tab.
+func csvDelimiter(s string) (rune, error) {
+	switch s {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("Delimiter '%s' is invalid delimiter.", s)
+	}
+	return r, nil
+}
+
+// csvForeignKeyTargets returns referenced columns of column like 'users.id'.
+// Table in other schema is qualified by schema.
+func csvForeignKeyTargets(tbl *dbmodel.Table, col *dbmodel.Column) string {
+	targets := make([]string, 0)
+	for _, fk := range tbl.ForeignKeys() {
+		for _, ref := range fk.ColumnReferences() {
+			if ref.From().Name() != col.Name() {
+				continue
+			}
+			to := ref.To()
+			target := to.TableName() + "." + to.Name()
+			if to.Schema() != tbl.Schema() {
+				target = to.Schema() + "." + target
+			}
+			targets = append(targets, target)
+		}
+	}
+	return strings.Join(targets, ", ")
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func publishCSVWithMigrations(locale string, delimiter string) (int, string, error) {
+	if err := initPublishMarkdownTest(); err != nil {
+		return 0, "", err
+	}
+	publishOpt.format = "csv"
+	publishOpt.locale = locale
+	publishOpt.delimiter = delimiter
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	stat := cmdPublish.Run([]string{})
+	path, err := resolvePath("out")
+	return stat, path, err
+}
+
+func TestCmdPublishCSV(t *testing.T) {
+	stat, dir, err := publishCSVWithMigrations("en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	expected := `Created: out/tables.csv
+Created: out/columns.csv
+Created: out/indices.csv
+Created: out/foreign_keys.csv
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	expected = `SCHEMA,TABLE,COMMENT
+public,posts,Posts of users
+public,users,
+`
+	if actual := readPublished(t, dir, "tables.csv"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `SCHEMA,TABLE,TABLE COMMENT,ORDINAL,COLUMN,TYPE,SIZE,NULLABLE,DEFAULT,PK,FK,COMMENT
+public,posts,Posts of users,1,id,int4,"32, 0",NO,nextval('posts_id_seq'::regclass),1,,
+public,posts,Posts of users,2,user_id,int4,"32, 0",NO,,,users.id,
+public,posts,Posts of users,3,title,text,,NO,,,,
+public,users,,1,id,int4,"32, 0",NO,nextval('users_id_seq'::regclass),1,,
+public,users,,2,display_name,varchar,50,NO,,,,
+public,users,,3,email,varchar,100,YES,,,,
+`
+	if actual := readPublished(t, dir, "columns.csv"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	if actual := readPublished(t, dir, "indices.csv"); !strings.HasPrefix(actual, "SCHEMA,TABLE,INDEX,COLUMNS,UNIQUE\n") || !strings.Contains(actual, "public,users,users_email_idx,email,YES\n") {
+		t.Errorf("indices.csv is invalid, actual:\n%v", actual)
+	}
+	expected = `SCHEMA,TABLE,FOREIGN KEY,COLUMNS,FOREIGN TABLE,FOREIGN COLUMNS
+public,posts,posts_user_id_fkey,user_id,users,id
+`
+	if actual := readPublished(t, dir, "foreign_keys.csv"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdPublishTSVWithJapaneseLocale(t *testing.T) {
+	stat, dir, err := publishCSVWithMigrations("ja", "tab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	expected := "スキーマ\tテーブル\tコメント\npublic\tposts\tPosts of users\npublic\tusers\t\n"
+	if actual := readPublished(t, dir, "tables.tsv"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = "スキーマ\tテーブル\tテーブルコメント\t順序\t列名\t型\tサイズ\tNULL許可\t初期値\tPK\t参照先\tコメント\n"
+	if actual := readPublished(t, dir, "columns.tsv"); !strings.HasPrefix(actual, expected) || !strings.Contains(actual, "public\tposts\tPosts of users\t2\tuser_id\tint4\t32, 0\tNO\t\t\tusers.id\t\n") {
+		t.Errorf("columns.tsv is invalid, actual:\n%v", actual)
+	}
+}
+
+func TestCmdPublishCSVWithInvalidDelimiter(t *testing.T) {
+	stat, _, err := publishCSVWithMigrations("en", "ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 1 {
+		t.Error("Publish subcommand should fail when delimiter is invalid.")
+	}
+	if actual := o.err.(*bytes.Buffer).String(); !strings.Contains(actual, "Delimiter 'ab' is invalid delimiter.") {
+		t.Errorf("expected: %v, actual: %v", "Delimiter 'ab' is invalid delimiter.", actual)
+	}
+}
+
+func TestCSVForeignKeyTargets(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id int PRIMARY KEY);
+CREATE SCHEMA hr;
+CREATE TABLE hr.staffs (id int PRIMARY KEY, shop_id int REFERENCES public.shops (id), boss_id int REFERENCES hr.staffs (id));
+`)
+	var staffs = tables[0]
+	for _, tbl := range tables {
+		if tbl.Name() == "staffs" {
+			staffs = tbl
+		}
+	}
+	if expected, actual := "public.shops.id", csvForeignKeyTargets(staffs, findColumn(staffs, "shop_id")); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := "staffs.id", csvForeignKeyTargets(staffs, findColumn(staffs, "boss_id")); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := "", csvForeignKeyTargets(staffs, findColumn(staffs, "id")); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
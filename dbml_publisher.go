@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+var (
+	dbmlNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	dbmlLiteralPattern   = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|true|false|null)$`)
+	dbmlSQLStringPattern = regexp.MustCompile(`^'(([^']|'')*)'$`)
+)
+
+// dbmlPublisher writes tables as DBML that dbdiagram.io and other tools accept.
+type dbmlPublisher struct {
+	basePublisher
+}
+
+func newDBMLPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *dbmlPublisher {
+	return &dbmlPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *dbmlPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	schema := p.cfg.Schema
+	if schema == "" && len(tables) > 0 {
+		schema = tables[0].Schema()
+	}
+	p.write("schema.dbml", convertToDBML(tables, p.conv, schema))
+}
+
+// convertToDBML makes Table blocks and Ref lines.
+// Names of tables in other schema than given schema are qualified like foreign key table of converter.
+// Ref is written only when referenced table is in tables, because DBML tools reject Ref to undefined table.
+func convertToDBML(tables []*dbmodel.Table, conv Converter, schema string) []byte {
+	buf := &bytes.Buffer{}
+	for i, tbl := range tables {
+		if i > 0 {
+			fmt.Fprintln(buf)
+		}
+		fmt.Fprintf(buf, "Table %s {\n", dbmlTableName(tbl.Schema(), tbl.Name(), schema))
+		pks, _ := splitPrimaryKeyColumns(tbl)
+		for _, col := range tbl.Columns() {
+			fmt.Fprintln(buf, "  "+dbmlColumn(col, conv, len(pks) == 1))
+		}
+
+		indices := make([]string, 0)
+		if len(pks) > 1 {
+			names := make([]string, 0, len(pks))
+			for _, col := range pks {
+				names = append(names, dbmlName(col.Name()))
+			}
+			indices = append(indices, fmt.Sprintf("(%s) [pk]", strings.Join(names, ", ")))
+		}
+		for _, idx := range tbl.Indices() {
+			if isPrimaryKeyIndex(tbl, idx) {
+				continue
+			}
+			settings := make([]string, 0, 2)
+			if idx.IsUnique() {
+				settings = append(settings, "unique")
+			}
+			settings = append(settings, "name: "+dbmlString(idx.Name()))
+			indices = append(indices, fmt.Sprintf("%s [%s]", dbmlColumnList(idx.Columns()), strings.Join(settings, ", ")))
+		}
+		if len(indices) > 0 {
+			fmt.Fprintln(buf)
+			fmt.Fprintln(buf, "  Indexes {")
+			for _, idx := range indices {
+				fmt.Fprintln(buf, "    "+idx)
+			}
+			fmt.Fprintln(buf, "  }")
+		}
+
+		if tbl.Comment() != "" {
+			fmt.Fprintln(buf)
+			fmt.Fprintf(buf, "  Note: %s\n", dbmlString(tbl.Comment()))
+		}
+		fmt.Fprintln(buf, "}")
+	}
+
+	defined := make(map[string]bool)
+	for _, tbl := range tables {
+		defined[ddlKey(tbl.Schema(), tbl.Name())] = true
+	}
+	refs := make([]string, 0)
+	for _, tbl := range tables {
+		for _, fk := range tbl.ForeignKeys() {
+			if len(fk.ColumnReferences()) == 0 {
+				continue
+			}
+			fromCols := make([]*dbmodel.Column, 0, len(fk.ColumnReferences()))
+			toCols := make([]*dbmodel.Column, 0, len(fk.ColumnReferences()))
+			for _, ref := range fk.ColumnReferences() {
+				fromCols = append(fromCols, ref.From())
+				toCols = append(toCols, ref.To())
+			}
+			to := toCols[0]
+			if !defined[ddlKey(to.Schema(), to.TableName())] {
+				continue
+			}
+			refs = append(refs, fmt.Sprintf("Ref %s: %s.%s > %s.%s", dbmlName(fk.Name()),
+				dbmlTableName(tbl.Schema(), tbl.Name(), schema), dbmlColumnList(fromCols),
+				dbmlTableName(to.Schema(), to.TableName(), schema), dbmlColumnList(toCols)))
+		}
+	}
+	if len(refs) > 0 {
+		fmt.Fprintln(buf)
+		for _, ref := range refs {
+			fmt.Fprintln(buf, ref)
+		}
+	}
+	return buf.Bytes()
+}
+
+// dbmlColumn returns column definition. pk setting is used when primary key is single column.
+func dbmlColumn(col *dbmodel.Column, conv Converter, singlePK bool) string {
+	values := conv.ConvertColumn(col)
+	settings := make([]string, 0)
+	if singlePK && col.PrimaryKeyPosition() > 0 {
+		settings = append(settings, "pk")
+	} else if !col.IsNullable() {
+		settings = append(settings, "not null")
+	}
+	if values[5] != "" {
+		settings = append(settings, "default: "+dbmlDefault(values[5]))
+	}
+	if col.Comment() != "" {
+		settings = append(settings, "note: "+dbmlString(col.Comment()))
+	}
+	def := dbmlName(col.Name()) + " " + dbmlType(diagramColumnType(conv, col))
+	if len(settings) > 0 {
+		def += " [" + strings.Join(settings, ", ") + "]"
+	}
+	return def
+}
+
+// dbmlColumnList returns column name, or names in parentheses when there are multiple columns.
+func dbmlColumnList(cols []*dbmodel.Column) string {
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, dbmlName(col.Name()))
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+func dbmlTableName(schema string, name string, base string) string {
+	if schema != base {
+		return dbmlName(schema) + "." + dbmlName(name)
+	}
+	return dbmlName(name)
+}
+
+func dbmlName(name string) string {
+	if dbmlNamePattern.MatchString(name) {
+		return name
+	}
+	return `"` + strings.Replace(name, `"`, `\"`, -1) + `"`
+}
+
+// dbmlType quotes type that has space or other characters like 'double precision'.
+func dbmlType(t string) string {
+	t = strings.Replace(t, ", ", ",", -1)
+	if strings.ContainsAny(t, " \"") || strings.Contains(t, "[]") {
+		return `"` + strings.Replace(t, `"`, `\"`, -1) + `"`
+	}
+	return t
+}
+
+// dbmlDefault returns number, boolean and null as is, string as string literal and other expression in backticks.
+func dbmlDefault(v string) string {
+	if dbmlLiteralPattern.MatchString(v) {
+		return v
+	}
+	if m := dbmlSQLStringPattern.FindStringSubmatch(v); m != nil {
+		return dbmlString(strings.Replace(m[1], "''", "'", -1))
+	}
+	return "`" + strings.Replace(v, "`", "\\`", -1) + "`"
+}
+
+// dbmlString returns string literal. Multi-line string is quoted by triple quotes.
+func dbmlString(s string) string {
+	if strings.Contains(s, "\n") {
+		return "'''" + strings.Replace(s, "'''", "\\'''", -1) + "'''"
+	}
+	return "'" + strings.Replace(strings.Replace(s, `\`, `\\`, -1), "'", `\'`, -1) + "'"
+}
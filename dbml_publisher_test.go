@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+func TestConvertToDBML(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (
+    id serial PRIMARY KEY,
+    name varchar(50) NOT NULL DEFAULT 'Shop''s name',
+    rate double precision DEFAULT 1.5,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+COMMENT ON TABLE shops IS 'Shops';
+COMMENT ON COLUMN shops.name IS 'Name of shop';
+CREATE TABLE order_items (
+    order_id int,
+    line int,
+    shop_id int NOT NULL REFERENCES shops (id),
+    "Item Code" text,
+    PRIMARY KEY (order_id, line)
+);
+CREATE UNIQUE INDEX order_items_code_idx ON order_items ("Item Code");
+CREATE INDEX order_items_shop_idx ON order_items (shop_id, line);
+CREATE SCHEMA hr;
+CREATE TABLE hr.staffs (id int PRIMARY KEY, shop_id int REFERENCES public.shops (id));
+COMMENT ON TABLE hr.staffs IS 'Staffs
+of shops';
+`)
+	actual := string(convertToDBML(tables, findConverter(true, "postgres"), "public"))
+	expected := `Table hr.staffs {
+  id integer [pk]
+  shop_id integer
+
+  Note: '''Staffs
+of shops'''
+}
+
+Table order_items {
+  order_id integer [not null]
+  line integer [not null]
+  shop_id integer [not null]
+  "Item Code" text
+
+  Indexes {
+    (order_id, line) [pk]
+    "Item Code" [unique, name: 'order_items_code_idx']
+    (shop_id, line) [name: 'order_items_shop_idx']
+  }
+}
+
+Table shops {
+  id serial [pk]
+  name varchar(50) [not null, default: 'Shop\'s name', note: 'Name of shop']
+  rate "double precision" [default: 1.5]
+  active bool [not null, default: true]
+  created_at timestamp(6) [not null, default: ` + "`now()`" + `]
+
+  Note: 'Shops'
+}
+
+Ref staffs_shop_id_fkey: hr.staffs.shop_id > shops.id
+Ref order_items_shop_id_fkey: order_items.shop_id > shops.id
+`
+	if expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestConvertToDBMLWithoutReferencedTable(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id serial PRIMARY KEY);
+CREATE SCHEMA hr;
+CREATE TABLE hr.staffs (id int PRIMARY KEY, shop_id int REFERENCES public.shops (id));
+`)
+	staffs := make([]*dbmodel.Table, 0, 1)
+	for _, tbl := range tables {
+		if tbl.Schema() == "hr" {
+			staffs = append(staffs, tbl)
+		}
+	}
+	actual := string(convertToDBML(staffs, findConverter(true, "postgres"), "hr"))
+	expected := `Table staffs {
+  id integer [pk]
+  shop_id integer
+}
+`
+	if expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdPublishDBML(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "dbml"
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	dbml := readPublished(t, dir, "schema.dbml")
+	for _, s := range []string{
+		"Table users {\n  id int4(32,0) [pk, default: `nextval('users_id_seq'::regclass)`]\n",
+		"    email [unique, name: 'users_email_idx']\n",
+		"  Note: 'Posts of users'\n",
+		"\nRef posts_user_id_fkey: posts.user_id > users.id\n",
+	} {
+		if !strings.Contains(dbml, s) {
+			t.Errorf("schema.dbml should contain %s, actual:\n%s", s, dbml)
+		}
+	}
+}
@@ -0,0 +1,927 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+var (
+	// columnConstraintWords are keywords that end data type or default value in column definition.
+	columnConstraintWords = []string{"NOT", "NULL", "CONSTRAINT", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "COLLATE", "GENERATED", "AUTO_INCREMENT", "AUTOINCREMENT", "COMMENT", "ON"}
+
+	// statementWords are leading keywords used for summary of unsupported statement.
+	statementWords = []string{"CREATE", "ALTER", "DROP", "COMMENT", "OR", "REPLACE", "ON", "TEMP", "TEMPORARY", "UNIQUE", "MATERIALIZED", "GLOBAL", "LOCAL", "UNLOGGED"}
+
+	postgresTypeAliases = map[string]string{
+		"int":                         "int4",
+		"integer":                     "int4",
+		"serial":                      "int4",
+		"serial4":                     "int4",
+		"smallint":                    "int2",
+		"smallserial":                 "int2",
+		"serial2":                     "int2",
+		"bigint":                      "int8",
+		"bigserial":                   "int8",
+		"serial8":                     "int8",
+		"real":                        "float4",
+		"float":                       "float8",
+		"double precision":            "float8",
+		"boolean":                     "bool",
+		"character varying":           "varchar",
+		"character":                   "bpchar",
+		"char":                        "bpchar",
+		"decimal":                     "numeric",
+		"timestamp without time zone": "timestamp",
+		"timestamp with time zone":    "timestamptz",
+		"time without time zone":      "time",
+		"time with time zone":         "timetz",
+	}
+
+	postgresIntegerPrecisions = map[string]int64{"int2": 16, "int4": 32, "int8": 64}
+	postgresFloatPrecisions   = map[string]int64{"float4": 24, "float8": 53}
+)
+
+// ddlParser applies DDL statements in SQL text to schema model.
+// Statements that are not supported are skipped and reported as warnings.
+type ddlParser struct {
+	schema        *ddlSchema
+	file          string
+	text          string
+	defaultSchema string
+	warnings      []string
+}
+
+// applySQL applies all statements in SQL text to schema model and returns warnings.
+func (s *ddlSchema) applySQL(file string, text string) []string {
+	p := &ddlParser{
+		schema:        s,
+		file:          file,
+		text:          text,
+		defaultSchema: s.defaultSchema,
+		warnings:      make([]string, 0),
+	}
+	for _, stmt := range splitSQLStatements(lexSQL(text)) {
+		if last := stmt[len(stmt)-1]; last.unterminated {
+			p.warn(last, "quoted text is not terminated. (skipped)")
+			continue
+		}
+		p.statement(stmt)
+	}
+	return p.warnings
+}
+
+func (p *ddlParser) warn(t sqlToken, format string, args ...interface{}) {
+	line := strings.Count(p.text[:t.pos], "\n") + 1
+	p.warnings = append(p.warnings, fmt.Sprintf("%s:%d: %s", p.file, line, fmt.Sprintf(format, args...)))
+}
+
+func (p *ddlParser) unsupported(tokens []sqlToken) {
+	words := make([]string, 0)
+	for _, t := range tokens {
+		if t.kind != sqlWord {
+			break
+		}
+		words = append(words, strings.ToUpper(t.text))
+		if !isOneOf(t, statementWords) {
+			break
+		}
+	}
+	p.warn(tokens[0], "'%s' is not supported. (skipped)", strings.Join(words, " "))
+}
+
+func (p *ddlParser) statement(tokens []sqlToken) {
+	c := p.cursor(tokens)
+	switch {
+	case c.is("BEGIN"), c.is("COMMIT"), c.is("ROLLBACK"), c.is("START"), c.is("END"):
+		// transaction control does not change schema.
+	case c.accept("SET"):
+		p.set(c)
+	case c.accept("CREATE", "SCHEMA"):
+		p.createSchema(c)
+	case c.is("CREATE"):
+		p.create(c)
+	case c.accept("ALTER", "TABLE"):
+		p.alterTable(c)
+	case c.accept("DROP", "TABLE"):
+		p.dropTable(c)
+	case c.accept("DROP", "INDEX"):
+		p.dropIndex(c)
+	case c.accept("COMMENT", "ON"):
+		p.commentOn(c)
+	default:
+		p.unsupported(tokens)
+	}
+}
+
+// set handles 'SET search_path' that changes schema of objects whose name is not qualified.
+func (p *ddlParser) set(c *sqlCursor) {
+	c.accept("SESSION")
+	c.accept("LOCAL")
+	if !c.accept("search_path") {
+		return
+	}
+	if !c.accept("TO") {
+		c.acceptSymbol("=")
+	}
+	if !c.eof() {
+		p.defaultSchema = c.ident()
+	}
+}
+
+func (p *ddlParser) createSchema(c *sqlCursor) {
+	c.accept("IF", "NOT", "EXISTS")
+	name := c.ident()
+	if c.accept("AUTHORIZATION") {
+		role := c.ident()
+		if name == "" {
+			name = role
+		}
+	}
+
+	// schema elements like 'CREATE SCHEMA foo CREATE TABLE bar (...) CREATE TABLE baz (...)'
+	saved := p.defaultSchema
+	p.defaultSchema = name
+	rest := c.rest()
+	start := 0
+	for i := 1; i <= len(rest); i++ {
+		if i == len(rest) || rest[i].is("CREATE") && depthAt(rest, i) == 0 {
+			if start < i {
+				p.statement(rest[start:i])
+			}
+			start = i
+		}
+	}
+	p.defaultSchema = saved
+}
+
+func (p *ddlParser) create(c *sqlCursor) {
+	start := c.peek()
+	c.accept("CREATE")
+	c.accept("OR", "REPLACE")
+	unique := c.accept("UNIQUE")
+	for c.accept("GLOBAL") || c.accept("LOCAL") || c.accept("TEMP") || c.accept("TEMPORARY") || c.accept("UNLOGGED") {
+	}
+	switch {
+	case c.accept("TABLE") && !unique:
+		p.createTable(c, start)
+	case c.accept("INDEX"):
+		p.createIndex(c, unique)
+	case c.accept("DOMAIN") && !unique:
+		p.createDomain(c)
+	default:
+		p.unsupported(c.tokens)
+	}
+}
+
+func (p *ddlParser) createTable(c *sqlCursor, start sqlToken) {
+	c.accept("IF", "NOT", "EXISTS")
+	schema, name := p.qualifiedName(c)
+	body, ok := c.parenGroup()
+	if !ok {
+		p.warn(start, "'CREATE TABLE %s' without column definitions is not supported. (skipped)", name)
+		return
+	}
+
+	// table is added to schema after whole definition is parsed.
+	tbl := &ddlTable{schema: schema, name: name}
+	for _, def := range splitByComma(body) {
+		if len(def) == 0 {
+			continue
+		}
+		dc := p.cursor(def)
+		if !p.tableConstraint(dc, tbl) {
+			p.columnDef(p.cursor(def), tbl)
+		}
+	}
+
+	// table options (MySQL)
+	for !c.eof() {
+		if c.accept("COMMENT") {
+			c.acceptSymbol("=")
+			tbl.comment = c.next().value()
+			continue
+		}
+		c.next()
+	}
+	p.schema.addTable(tbl)
+}
+
+// columnDef parses column definition and adds column to table.
+func (p *ddlParser) columnDef(c *sqlCursor, tbl *ddlTable) *ddlColumn {
+	col := &ddlColumn{name: c.ident(), nullable: true}
+	if col.name == "" {
+		return nil
+	}
+	serial := p.setColumnType(col, tbl, c.until(columnConstraintWords))
+	if serial {
+		col.nullable = false
+		col.defVal = fmt.Sprintf("nextval('%s'::regclass)", postgresSequenceName(tbl.name, col.name))
+	}
+	if old := tbl.column(col.name); old != nil {
+		*old = *col
+		col = old
+	} else {
+		tbl.columns = append(tbl.columns, col)
+	}
+	p.columnConstraints(c, tbl, col)
+	return col
+}
+
+func (p *ddlParser) columnConstraints(c *sqlCursor, tbl *ddlTable, col *ddlColumn) {
+	name := ""
+	for !c.eof() {
+		switch {
+		case c.accept("CONSTRAINT"):
+			name = c.ident()
+			continue
+		case c.accept("NOT", "NULL"):
+			col.nullable = false
+		case c.accept("NULL"):
+			col.nullable = true
+		case c.accept("DEFAULT"):
+			if c.accept("NULL") {
+				col.defVal = ""
+			} else {
+				col.defVal = p.defaultValueText(c.until(columnConstraintWords))
+			}
+		case c.accept("PRIMARY", "KEY"):
+			tbl.setPrimaryKey(p.primaryKeyName(name), []string{col.name})
+		case c.accept("UNIQUE"):
+			c.accept("KEY")
+			if name == "" {
+				name = defaultConstraintName(tbl.name, []string{col.name}, "key")
+			}
+			tbl.addIndex(&ddlIndex{name: name, columns: []string{col.name}, unique: true})
+		case c.accept("REFERENCES"):
+			if name == "" {
+				name = defaultConstraintName(tbl.name, []string{col.name}, "fkey")
+			}
+			p.references(c, tbl, name, []string{col.name})
+		case c.accept("CHECK"):
+			group, _ := c.parenGroupTokens()
+			if name == "" {
+				name = defaultConstraintName(tbl.name, []string{col.name}, "check")
+			}
+			tbl.addConstraint(&ddlConstraint{name: name, kind: "CHECK", content: sourceText(p.text, group)})
+		case c.accept("AUTO_INCREMENT"), c.accept("AUTOINCREMENT"):
+			if p.schema.driver == "mysql" {
+				col.dataType = mysqlColumnType(col.dataType, "auto_increment")
+			}
+		case c.accept("COMMENT"):
+			col.comment = c.next().value()
+		default:
+			c.next()
+		}
+		name = ""
+	}
+}
+
+// tableConstraint parses table constraint. It returns false when tokens are not table constraint.
+func (p *ddlParser) tableConstraint(c *sqlCursor, tbl *ddlTable) bool {
+	name := ""
+	if c.accept("CONSTRAINT") {
+		name = c.ident()
+	}
+	switch {
+	case c.accept("PRIMARY", "KEY"):
+		cols, _ := p.columnList(c)
+		tbl.setPrimaryKey(p.primaryKeyName(name), cols)
+	case c.accept("UNIQUE"):
+		if !c.accept("KEY") {
+			c.accept("INDEX")
+		}
+		if c.peek().text != "(" {
+			name = c.ident()
+		}
+		cols, _ := p.columnList(c)
+		if name == "" {
+			name = defaultConstraintName(tbl.name, cols, "key")
+		}
+		tbl.addIndex(&ddlIndex{name: name, columns: cols, unique: true})
+	case c.accept("FOREIGN", "KEY"):
+		if c.peek().text != "(" {
+			c.ident()
+		}
+		cols, _ := p.columnList(c)
+		if !c.accept("REFERENCES") {
+			return true
+		}
+		if name == "" {
+			name = defaultConstraintName(tbl.name, cols, "fkey")
+		}
+		p.references(c, tbl, name, cols)
+	case c.accept("CHECK"):
+		group, _ := c.parenGroupTokens()
+		if name == "" {
+			name = defaultConstraintName(tbl.name, nil, "check")
+		}
+		tbl.addConstraint(&ddlConstraint{name: name, kind: "CHECK", content: sourceText(p.text, group)})
+	case name == "" && (c.is("KEY") || c.is("INDEX")):
+		// inline index of MySQL
+		c.next()
+		if c.peek().text != "(" {
+			name = c.ident()
+		}
+		cols, _ := p.columnList(c)
+		tbl.addIndex(&ddlIndex{name: name, columns: cols})
+	default:
+		return name != ""
+	}
+	return true
+}
+
+// primaryKeyName returns name of primary key. MySQL always names it 'PRIMARY'.
+func (p *ddlParser) primaryKeyName(name string) string {
+	if p.schema.driver == "mysql" {
+		return "PRIMARY"
+	}
+	return name
+}
+
+func (p *ddlParser) references(c *sqlCursor, tbl *ddlTable, name string, cols []string) {
+	schema, refTable := p.qualifiedName(c)
+	var refCols []string
+	if c.peek().text == "(" {
+		refCols, _ = p.columnList(c)
+	}
+	// ON DELETE, ON UPDATE, MATCH and DEFERRABLE are ignored.
+	for c.accept("ON") {
+		c.next()
+		if !c.accept("NO", "ACTION") && !c.accept("SET", "NULL") && !c.accept("SET", "DEFAULT") {
+			c.next()
+		}
+	}
+	tbl.addForeignKey(&ddlForeignKey{
+		name:       name,
+		columns:    cols,
+		refSchema:  schema,
+		refTable:   refTable,
+		refColumns: refCols,
+	})
+}
+
+func (p *ddlParser) createIndex(c *sqlCursor, unique bool) {
+	c.accept("CONCURRENTLY")
+	c.accept("IF", "NOT", "EXISTS")
+	name := ""
+	if !c.is("ON") {
+		name = c.ident()
+	}
+	start := c.peek()
+	if !c.accept("ON") {
+		p.unsupported(c.tokens)
+		return
+	}
+	c.accept("ONLY")
+	schema, tblName := p.qualifiedName(c)
+	tbl := p.schema.table(schema, tblName)
+	if tbl == nil {
+		p.warn(start, "table '%s' of index '%s' is not found. (skipped)", tblName, name)
+		return
+	}
+	if c.accept("USING") {
+		c.next()
+	}
+	cols, _ := p.columnList(c)
+	if name == "" {
+		name = defaultConstraintName(tbl.name, cols, "idx")
+	}
+	tbl.addIndex(&ddlIndex{name: name, columns: cols, unique: unique})
+}
+
+func (p *ddlParser) dropTable(c *sqlCursor) {
+	c.accept("IF", "EXISTS")
+	for {
+		schema, name := p.qualifiedName(c)
+		p.schema.dropTable(schema, name)
+		if !c.acceptSymbol(",") {
+			return
+		}
+	}
+}
+
+func (p *ddlParser) dropIndex(c *sqlCursor) {
+	c.accept("CONCURRENTLY")
+	c.accept("IF", "EXISTS")
+	schema, name := p.qualifiedName(c)
+	if c.accept("ON") {
+		// MySQL: DROP INDEX name ON table
+		schema, tblName := p.qualifiedName(c)
+		if tbl := p.schema.table(schema, tblName); tbl != nil {
+			tbl.dropIndex(name)
+		}
+		return
+	}
+	for _, tbl := range p.schema.tables {
+		if tbl.schema == schema && tbl.dropIndex(name) {
+			return
+		}
+	}
+}
+
+func (p *ddlParser) createDomain(c *sqlCursor) {
+	schema, name := p.qualifiedName(c)
+	c.accept("AS")
+	d := &ddlDomain{schema: schema, name: name}
+	col := &ddlColumn{}
+	p.setColumnType(col, nil, c.until(columnConstraintWords))
+	d.dataType = col.dataType
+	d.size = col.size
+	p.schema.domains[ddlKey(schema, name)] = d
+}
+
+func (p *ddlParser) alterTable(c *sqlCursor) {
+	c.accept("IF", "EXISTS")
+	c.accept("ONLY")
+	schema, name := p.qualifiedName(c)
+	tbl := p.schema.table(schema, name)
+	if tbl == nil {
+		p.warn(c.tokens[0], "table '%s' is not found. (skipped)", name)
+		return
+	}
+	for _, action := range splitByComma(c.rest()) {
+		if len(action) > 0 && !p.alterTableAction(p.cursor(action), tbl) {
+			p.warn(action[0], "'ALTER TABLE %s %s' is not supported. (skipped)", name, strings.ToUpper(action[0].text))
+		}
+	}
+}
+
+// alterTableAction applies an action of ALTER TABLE. It returns false when action is not supported.
+func (p *ddlParser) alterTableAction(c *sqlCursor, tbl *ddlTable) bool {
+	switch {
+	case c.accept("ADD"):
+		if c.is("CONSTRAINT") || c.is("PRIMARY") || c.is("UNIQUE") || c.is("FOREIGN") || c.is("CHECK") || c.is("KEY") || c.is("INDEX") {
+			return p.tableConstraint(c, tbl)
+		}
+		c.accept("COLUMN")
+		c.accept("IF", "NOT", "EXISTS")
+		p.columnDef(c, tbl)
+	case c.accept("DROP", "CONSTRAINT"), c.accept("DROP", "FOREIGN", "KEY"):
+		c.accept("IF", "EXISTS")
+		tbl.dropConstraint(c.ident())
+	case c.accept("DROP", "PRIMARY", "KEY"):
+		tbl.dropConstraint(tbl.pkName)
+	case c.accept("DROP", "INDEX"), c.accept("DROP", "KEY"):
+		tbl.dropIndex(c.ident())
+	case c.accept("DROP"):
+		c.accept("COLUMN")
+		c.accept("IF", "EXISTS")
+		tbl.dropColumn(c.ident())
+	case c.accept("ALTER"):
+		c.accept("COLUMN")
+		col := tbl.column(c.ident())
+		if col == nil {
+			return false
+		}
+		return p.alterColumn(c, tbl, col)
+	case c.accept("RENAME", "TO"), c.accept("RENAME", "AS"):
+		p.schema.renameTable(tbl, c.ident())
+	case c.accept("RENAME"):
+		c.accept("COLUMN")
+		old := c.ident()
+		if !c.accept("TO") || tbl.column(old) == nil {
+			return false
+		}
+		p.schema.renameColumn(tbl, old, c.ident())
+	case c.accept("MODIFY"):
+		// MySQL: redefine column
+		c.accept("COLUMN")
+		return p.columnDef(c, tbl) != nil
+	case c.accept("CHANGE"):
+		// MySQL: rename and redefine column
+		c.accept("COLUMN")
+		old := c.ident()
+		if tbl.column(old) == nil {
+			return false
+		}
+		p.schema.renameColumn(tbl, old, c.peek().ident())
+		return p.columnDef(c, tbl) != nil
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *ddlParser) alterColumn(c *sqlCursor, tbl *ddlTable, col *ddlColumn) bool {
+	switch {
+	case c.accept("TYPE"), c.accept("SET", "DATA", "TYPE"):
+		p.setColumnType(col, tbl, c.until([]string{"USING", "COLLATE"}))
+	case c.accept("SET", "DEFAULT"):
+		col.defVal = p.defaultValueText(c.rest())
+	case c.accept("DROP", "DEFAULT"):
+		col.defVal = ""
+	case c.accept("SET", "NOT", "NULL"):
+		col.nullable = false
+	case c.accept("DROP", "NOT", "NULL"):
+		col.nullable = true
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *ddlParser) commentOn(c *sqlCursor) {
+	start := c.tokens[0]
+	isTable := c.accept("TABLE")
+	if !isTable && !c.accept("COLUMN") {
+		p.unsupported(c.tokens)
+		return
+	}
+	parts := p.nameParts(c)
+	if !c.accept("IS") {
+		return
+	}
+	comment := ""
+	if !c.accept("NULL") {
+		comment = c.next().value()
+	}
+
+	if isTable {
+		schema, name := p.splitName(parts)
+		if tbl := p.schema.table(schema, name); tbl != nil {
+			tbl.comment = comment
+			return
+		}
+		p.warn(start, "table '%s' is not found. (skipped)", name)
+		return
+	}
+	if len(parts) < 2 {
+		return
+	}
+	schema, name := p.splitName(parts[:len(parts)-1])
+	tbl := p.schema.table(schema, name)
+	if tbl == nil {
+		p.warn(start, "table '%s' is not found. (skipped)", name)
+		return
+	}
+	if col := tbl.column(parts[len(parts)-1]); col != nil {
+		col.comment = comment
+	}
+}
+
+// setColumnType sets data type and size of column from type tokens.
+// It returns true when type is serial type of PostgreSQL.
+func (p *ddlParser) setColumnType(col *ddlColumn, tbl *ddlTable, tokens []sqlToken) bool {
+	if len(tokens) == 0 {
+		col.dataType = ""
+		col.size = emptySize()
+		return false
+	}
+	if d := p.domain(tokens); d != nil {
+		col.dataType = d.schema + "." + d.name
+		col.size = d.size
+		return false
+	}
+
+	declared := sourceText(p.text, tokens)
+	switch p.schema.driver {
+	case "postgres":
+		dataType, size, serial := postgresType(declared)
+		col.dataType = dataType
+		col.size = size
+		return serial
+	case "mysql":
+		_, size := parseDeclaredType(declared)
+		col.dataType = strings.ToLower(declared)
+		col.size = size
+	default:
+		col.dataType, col.size = parseDeclaredType(declared)
+	}
+	return false
+}
+
+// domain returns domain when type tokens are name of domain.
+func (p *ddlParser) domain(tokens []sqlToken) *ddlDomain {
+	names := make([]string, 0, 2)
+	for i, t := range tokens {
+		if i%2 == 1 {
+			if t.text != "." {
+				return nil
+			}
+			continue
+		}
+		if t.kind != sqlWord && t.kind != sqlQuotedIdent {
+			return nil
+		}
+		names = append(names, p.identOf(t))
+	}
+	schema, name := p.splitName(names)
+	if d, ok := p.schema.domains[ddlKey(schema, name)]; ok {
+		return d
+	}
+	// domain whose name is not qualified is also searched in default schema. (like search_path)
+	if len(names) == 1 {
+		return p.schema.domains[ddlKey(p.schema.defaultSchema, name)]
+	}
+	return nil
+}
+
+// postgresType converts declared type to type name and size that PostgreSQL catalog shows.
+func postgresType(declared string) (string, dbmodel.Size, bool) {
+	base := strings.ToLower(declared)
+	array := strings.HasSuffix(base, "[]")
+	base = strings.TrimSpace(strings.TrimSuffix(base, "[]"))
+	var params []int64
+	if i := strings.Index(base, "("); i >= 0 {
+		j := strings.Index(base, ")")
+		if j > i {
+			for _, s := range strings.Split(base[i+1:j], ",") {
+				if n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+					params = append(params, n)
+				}
+			}
+			base = strings.TrimSpace(base[:i] + " " + strings.TrimSpace(base[j+1:]))
+		}
+	}
+	base = strings.Join(strings.Fields(base), " ")
+	serial := strings.Contains(base, "serial")
+	if alias, ok := postgresTypeAliases[base]; ok {
+		base = alias
+	}
+
+	length := sql.NullInt64{}
+	precision := sql.NullInt64{}
+	scale := sql.NullInt64{}
+	switch base {
+	case "int2", "int4", "int8":
+		precision = sql.NullInt64{Int64: postgresIntegerPrecisions[base], Valid: true}
+		scale = sql.NullInt64{Int64: 0, Valid: true}
+	case "float4", "float8":
+		precision = sql.NullInt64{Int64: postgresFloatPrecisions[base], Valid: true}
+	case "varchar", "bpchar":
+		if len(params) > 0 {
+			length = sql.NullInt64{Int64: params[0], Valid: true}
+		} else if base == "bpchar" {
+			length = sql.NullInt64{Int64: 1, Valid: true}
+		}
+	case "numeric":
+		if len(params) > 0 {
+			precision = sql.NullInt64{Int64: params[0], Valid: true}
+			scale = sql.NullInt64{Int64: 0, Valid: true}
+		}
+		if len(params) > 1 {
+			scale = sql.NullInt64{Int64: params[1], Valid: true}
+		}
+	case "timestamp", "timestamptz", "time", "timetz":
+		precision = sql.NullInt64{Int64: 6, Valid: true}
+		if len(params) > 0 {
+			precision = sql.NullInt64{Int64: params[0], Valid: true}
+		}
+	}
+	if array {
+		return "_" + base, emptySize(), false
+	}
+	return base, dbmodel.NewSize(length, precision, scale), serial
+}
+
+// defaultValueText returns default value expression. Parentheses around whole expression are removed.
+// On PostgreSQL, keywords and function names are folded to lower case like catalog.
+func (p *ddlParser) defaultValueText(tokens []sqlToken) string {
+	for len(tokens) > 2 && tokens[0].text == "(" && matchParen(tokens, 0) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return ""
+	}
+	if p.schema.driver != "postgres" {
+		return sourceText(p.text, tokens)
+	}
+	// lower case of word may have different length, so spaces between tokens are copied from source.
+	buf := make([]byte, 0)
+	end := tokens[0].pos
+	for _, t := range tokens {
+		buf = append(buf, p.text[end:t.pos]...)
+		if t.kind == sqlWord {
+			buf = append(buf, strings.ToLower(t.text)...)
+		} else {
+			buf = append(buf, t.text...)
+		}
+		end = t.pos + len(t.text)
+	}
+	return string(buf)
+}
+
+// columnList parses column names in parentheses. Expression in index is ignored.
+func (p *ddlParser) columnList(c *sqlCursor) ([]string, bool) {
+	group, ok := c.parenGroup()
+	if !ok {
+		return nil, false
+	}
+	cols := make([]string, 0)
+	for _, elem := range splitByComma(group) {
+		if len(elem) == 0 || elem[0].kind != sqlWord && elem[0].kind != sqlQuotedIdent {
+			continue
+		}
+		if len(elem) > 1 && elem[1].text == "(" && elem[0].kind == sqlWord {
+			// function call, or length of prefix index (MySQL)
+			if matchParen(elem, 1) != len(elem)-1 || !isNumberGroup(elem[1:]) {
+				continue
+			}
+		}
+		cols = append(cols, p.identOf(elem[0]))
+	}
+	return cols, true
+}
+
+func isNumberGroup(tokens []sqlToken) bool {
+	return len(tokens) == 3 && tokens[1].kind == sqlNumber
+}
+
+func (p *ddlParser) qualifiedName(c *sqlCursor) (string, string) {
+	return p.splitName(p.nameParts(c))
+}
+
+func (p *ddlParser) nameParts(c *sqlCursor) []string {
+	parts := []string{c.ident()}
+	for c.acceptSymbol(".") {
+		parts = append(parts, c.ident())
+	}
+	return parts
+}
+
+// splitName splits name parts to schema and name. Schema is default schema when it is omitted.
+func (p *ddlParser) splitName(parts []string) (string, string) {
+	switch len(parts) {
+	case 0:
+		return p.defaultSchema, ""
+	case 1:
+		return p.defaultSchema, parts[0]
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+// identOf returns identifier name. On PostgreSQL, unquoted identifier is folded to lower case,
+// and identifier is truncated to postgresNameLength bytes like catalog.
+func (p *ddlParser) identOf(t sqlToken) string {
+	if p.schema.driver != "postgres" {
+		return t.ident()
+	}
+	if t.kind == sqlWord {
+		return clipIdentifier(strings.ToLower(t.text), postgresNameLength)
+	}
+	return clipIdentifier(t.ident(), postgresNameLength)
+}
+
+func (p *ddlParser) cursor(tokens []sqlToken) *sqlCursor {
+	return &sqlCursor{tokens: tokens, identOf: p.identOf}
+}
+
+// sqlCursor is reader of tokens in a statement.
+type sqlCursor struct {
+	tokens  []sqlToken
+	i       int
+	identOf func(sqlToken) string
+}
+
+func (c *sqlCursor) eof() bool {
+	return c.i >= len(c.tokens)
+}
+
+func (c *sqlCursor) peek() sqlToken {
+	if c.eof() {
+		return sqlToken{kind: sqlSymbol}
+	}
+	return c.tokens[c.i]
+}
+
+func (c *sqlCursor) next() sqlToken {
+	t := c.peek()
+	if !c.eof() {
+		c.i++
+	}
+	return t
+}
+
+func (c *sqlCursor) is(keyword string) bool {
+	return c.peek().is(keyword)
+}
+
+// accept consumes tokens when they are given keywords.
+func (c *sqlCursor) accept(keywords ...string) bool {
+	if c.i+len(keywords) > len(c.tokens) {
+		return false
+	}
+	for j, kw := range keywords {
+		if !c.tokens[c.i+j].is(kw) {
+			return false
+		}
+	}
+	c.i += len(keywords)
+	return true
+}
+
+func (c *sqlCursor) acceptSymbol(s string) bool {
+	if t := c.peek(); t.kind == sqlSymbol && t.text == s {
+		c.i++
+		return true
+	}
+	return false
+}
+
+// ident consumes identifier. It returns empty string when next token is not identifier.
+func (c *sqlCursor) ident() string {
+	t := c.peek()
+	if t.kind != sqlWord && t.kind != sqlQuotedIdent && t.kind != sqlString {
+		return ""
+	}
+	c.i++
+	if t.kind == sqlString {
+		return t.value()
+	}
+	return c.identOf(t)
+}
+
+// parenGroup consumes tokens in parentheses and returns inner tokens.
+func (c *sqlCursor) parenGroup() ([]sqlToken, bool) {
+	group, ok := c.parenGroupTokens()
+	if !ok || len(group) < 2 || group[len(group)-1].text != ")" {
+		return nil, false
+	}
+	return group[1 : len(group)-1], true
+}
+
+// parenGroupTokens consumes tokens in parentheses and returns them with parentheses.
+func (c *sqlCursor) parenGroupTokens() ([]sqlToken, bool) {
+	if c.peek().text != "(" || c.peek().kind != sqlSymbol {
+		return nil, false
+	}
+	end := matchParen(c.tokens, c.i)
+	group := c.tokens[c.i : end+1]
+	c.i = end + 1
+	return group, true
+}
+
+// until consumes tokens until one of keywords appears outside parentheses.
+func (c *sqlCursor) until(keywords []string) []sqlToken {
+	start := c.i
+	depth := 0
+	for !c.eof() {
+		t := c.peek()
+		if depth == 0 && isOneOf(t, keywords) {
+			break
+		}
+		if t.kind == sqlSymbol && t.text == "(" {
+			depth++
+		} else if t.kind == sqlSymbol && t.text == ")" {
+			depth--
+		}
+		c.i++
+	}
+	return c.tokens[start:c.i]
+}
+
+func (c *sqlCursor) rest() []sqlToken {
+	ts := c.tokens[c.i:]
+	c.i = len(c.tokens)
+	return ts
+}
+
+func isOneOf(t sqlToken, keywords []string) bool {
+	for _, kw := range keywords {
+		if t.is(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitByComma splits tokens by comma outside parentheses.
+func splitByComma(tokens []sqlToken) [][]sqlToken {
+	parts := make([][]sqlToken, 0)
+	depth := 0
+	start := 0
+	for i, t := range tokens {
+		if t.kind != sqlSymbol {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// depthAt returns depth of parentheses at token i.
+func depthAt(tokens []sqlToken, i int) int {
+	depth := 0
+	for _, t := range tokens[:i] {
+		if t.kind == sqlSymbol && t.text == "(" {
+			depth++
+		} else if t.kind == sqlSymbol && t.text == ")" {
+			depth--
+		}
+	}
+	return depth
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+func TestDDLSchemaApplySQL(t *testing.T) {
+	s := newDDLSchema(&Config{Driver: "postgres"})
+	warnings := s.applySQL("test.sql", `
+CREATE TABLE Customer (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    email text UNIQUE,
+    rank numeric(3, 1) DEFAULT 0.0 CHECK (rank >= 0)
+);
+CREATE TABLE "Order" (
+    id integer NOT NULL,
+    customer_id bigint REFERENCES customer,
+    ordered_at timestamp(3) with time zone DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE "Order" ADD CONSTRAINT order_pk PRIMARY KEY (id);
+CREATE UNIQUE INDEX order_customer_idx ON "Order" USING btree (customer_id, lower(id::text));
+COMMENT ON TABLE customer IS 'Customers';
+COMMENT ON COLUMN customer.name IS 'Customer''s name';
+CREATE VIEW v AS SELECT 1;
+ALTER TABLE missing ADD COLUMN foo int;
+`)
+	tables := s.build()
+	if len(tables) != 2 {
+		t.Fatalf("expected: %v, actual: %v", 2, len(tables))
+	}
+	order, customer := tables[0], tables[1]
+	if customer.Name() != "customer" || customer.Comment() != "Customers" {
+		t.Errorf("expected: %v, actual: %v", "customer(Customers)", customer.Name()+"("+customer.Comment()+")")
+	}
+
+	conv := findConverter(false, "postgres")
+	expectedCols := [][]string{
+		{"1", "id", "int8", "64, 0", "NO", "nextval('customer_id_seq'::regclass)", ""},
+		{"", "name", "varchar", "100", "NO", "", "Customer's name"},
+		{"", "email", "text", "", "", "", ""},
+		{"", "rank", "numeric", "3, 1", "", "0.0", ""},
+	}
+	for i, col := range customer.Columns() {
+		if expected, actual := strings.Join(expectedCols[i], "|"), strings.Join(conv.ConvertColumn(col), "|"); expected != actual {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+	if expected, actual := "timestamptz", order.Columns()[2].DataType(); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := "current_timestamp", order.Columns()[2].DefaultValue(); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	actualIdxs := make([]string, 0)
+	for _, idx := range customer.Indices() {
+		actualIdxs = append(actualIdxs, strings.Join(conv.ConvertIndex(idx), "|"))
+	}
+	for _, idx := range order.Indices() {
+		actualIdxs = append(actualIdxs, strings.Join(conv.ConvertIndex(idx), "|"))
+	}
+	expectedIdxs := "customer_email_key|email|YES,customer_pkey|id|YES,order_customer_idx|customer_id|YES,order_pk|id|YES"
+	if actual := strings.Join(actualIdxs, ","); expectedIdxs != actual {
+		t.Errorf("expected: %v, actual: %v", expectedIdxs, actual)
+	}
+
+	if expected, actual := "customer_rank_check|CHECK|(rank >= 0)", strings.Join(conv.ConvertConstraint(customer.Constraints()[0]), "|"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := "Order_customer_id_fkey|customer_id|customer|id", strings.Join(conv.ConvertForeignKey(order.ForeignKeys()[0]), "|"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := 1, len(customer.ReferencedKeys()); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	expectedWarnings := []string{
+		"test.sql:17: 'CREATE VIEW' is not supported. (skipped)",
+		"test.sql:18: table 'missing' is not found. (skipped)",
+	}
+	if expected, actual := strings.Join(expectedWarnings, "\n"), strings.Join(warnings, "\n"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestDDLSchemaApplySQLWithMysql(t *testing.T) {
+	s := newDDLSchema(&Config{Driver: "mysql", Database: "shop"})
+	warnings := s.applySQL("test.sql", "CREATE TABLE `items` (\n"+
+		"  `id` int(10) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',\n"+
+		"  `code` varchar(20) NOT NULL,\n"+
+		"  `price` decimal(10,2) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`),\n"+
+		"  UNIQUE KEY `uk_code` (`code`),\n"+
+		"  KEY `idx_code_price` (`code`(10), `price`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Items';")
+	if len(warnings) != 0 {
+		t.Errorf("expected: %v, actual: %v", 0, warnings)
+	}
+	tables := s.build()
+	if len(tables) != 1 {
+		t.Fatalf("expected: %v, actual: %v", 1, len(tables))
+	}
+	tbl := tables[0]
+	if expected, actual := "shop.items(Items)", tbl.Schema()+"."+tbl.Name()+"("+tbl.Comment()+")"; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	conv := findConverter(true, "mysql")
+	expectedCols := [][]string{
+		{"1", "id", "int unsigned auto_increment", "", "NO", "", "ID"},
+		{"", "code", "varchar", "20", "NO", "", ""},
+		{"", "price", "decimal", "10, 2", "", "", ""},
+	}
+	for i, col := range tbl.Columns() {
+		if expected, actual := strings.Join(expectedCols[i], "|"), strings.Join(conv.ConvertColumn(col), "|"); expected != actual {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+
+	actualIdxs := make([]string, 0)
+	for _, idx := range tbl.Indices() {
+		actualIdxs = append(actualIdxs, strings.Join(conv.ConvertIndex(idx), "|"))
+	}
+	expectedIdxs := "PRIMARY|id|YES,idx_code_price|code, price|,uk_code|code|YES"
+	if actual := strings.Join(actualIdxs, ","); expectedIdxs != actual {
+		t.Errorf("expected: %v, actual: %v", expectedIdxs, actual)
+	}
+}
+
+func TestDDLSchemaApplySQLWithRenameAndDrop(t *testing.T) {
+	s := newDDLSchema(&Config{Driver: "postgres"})
+	warnings := s.applySQL("test.sql", `
+CREATE TABLE users (id integer PRIMARY KEY, name text, tmp text);
+CREATE TABLE posts (id integer PRIMARY KEY, user_id integer REFERENCES users (id));
+CREATE INDEX users_tmp_idx ON users (tmp);
+CREATE TABLE tmp (id integer);
+ALTER TABLE users RENAME TO accounts;
+ALTER TABLE accounts RENAME COLUMN id TO account_id;
+ALTER TABLE accounts DROP COLUMN tmp;
+DROP TABLE IF EXISTS tmp;
+`)
+	if len(warnings) != 0 {
+		t.Errorf("expected: %v, actual: %v", 0, warnings)
+	}
+	tables := s.build()
+	if len(tables) != 2 {
+		t.Fatalf("expected: %v, actual: %v", 2, len(tables))
+	}
+	accounts, posts := tables[0], tables[1]
+	if expected, actual := "accounts", accounts.Name(); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := 2, len(accounts.Columns()); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := 1, len(accounts.Indices()); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	conv := findConverter(false, "postgres")
+	if expected, actual := "posts_user_id_fkey|user_id|accounts|account_id", strings.Join(conv.ConvertForeignKey(posts.ForeignKeys()[0]), "|"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestDDLSchemaApplySQLWithBrokenStatements(t *testing.T) {
+	tests := map[string]string{
+		"CREATE TABLE foo (":                          "test.sql:1: 'CREATE TABLE foo' without column definitions is not supported. (skipped)",
+		"CREATE TABLE foo (id int DEFAULT NOT NULL);": "",
+		"CREATE TABLE foo (id int);\nALTER TABLE foo ALTER COLUMN id SET DEFAULT;": "",
+		`CREATE TABLE "`: "test.sql:1: quoted text is not terminated. (skipped)",
+		"CREATE TABLE foo (id int);\nCOMMENT ON TABLE foo IS '": "test.sql:2: quoted text is not terminated. (skipped)",
+		"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1":        "test.sql:1: quoted text is not terminated. (skipped)",
+		"CREATE TABLE foo (id text DEFAULT İ || 'x');":          "",
+	}
+	for sql, expected := range tests {
+		s := newDDLSchema(&Config{Driver: "postgres"})
+		if actual := strings.Join(s.applySQL("test.sql", sql), "\n"); expected != actual {
+			t.Errorf("%q\nexpected: %v, actual: %v", sql, expected, actual)
+		}
+		s.build()
+	}
+}
+
+func TestDDLSchemaApplySQLTruncatesPostgresIdentifiers(t *testing.T) {
+	long := strings.Repeat("a", 40)
+	s := newDDLSchema(&Config{Driver: "postgres"})
+	s.applySQL("test.sql", `
+CREATE TABLE parents (id int PRIMARY KEY);
+CREATE TABLE `+long+` (`+strings.Repeat("b", 30)+` int REFERENCES parents);
+ALTER TABLE `+long+` ADD CONSTRAINT "fk_sales_order_header_sales_reason_sales_order_header_sales_order_id" FOREIGN KEY (`+strings.Repeat("b", 30)+`) REFERENCES parents;
+`)
+	tables := s.build()
+	var child *dbmodel.Table
+	for _, tbl := range tables {
+		if tbl.Name() != "parents" {
+			child = tbl
+		}
+	}
+	if expected := long; child == nil || expected != child.Name() {
+		t.Fatalf("expected: %v, actual: %v", expected, tables)
+	}
+	names := make([]string, 0)
+	for _, fk := range child.ForeignKeys() {
+		names = append(names, fk.Name())
+	}
+	sort.Strings(names)
+	expected := strings.Repeat("a", 29) + "_" + strings.Repeat("b", 28) + "_fkey, fk_sales_order_header_sales_reason_sales_order_header_sales_ord"
+	if actual := strings.Join(names, ", "); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestClipIdentifier(t *testing.T) {
+	name := "a" + strings.Repeat("テ", 30)
+	if expected, actual := "a"+strings.Repeat("テ", 20), clipIdentifier(name, postgresNameLength); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := "users", clipIdentifier("users", postgresNameLength); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
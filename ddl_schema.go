@@ -0,0 +1,390 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// postgresNameLength is max length of identifier in bytes on PostgreSQL. (NAMEDATALEN - 1)
+const postgresNameLength = 63
+
+// ddlSchema is in-memory schema model that DDL statements are applied to.
+type ddlSchema struct {
+	driver        string
+	defaultSchema string
+	tables        map[string]*ddlTable
+	domains       map[string]*ddlDomain
+}
+
+type ddlTable struct {
+	schema      string
+	name        string
+	comment     string
+	columns     []*ddlColumn
+	pkName      string
+	primaryKey  []string
+	indices     []*ddlIndex
+	constraints []*ddlConstraint
+	foreignKeys []*ddlForeignKey
+}
+
+type ddlColumn struct {
+	name     string
+	comment  string
+	dataType string
+	size     dbmodel.Size
+	nullable bool
+	defVal   string
+}
+
+type ddlIndex struct {
+	name    string
+	columns []string
+	unique  bool
+}
+
+type ddlConstraint struct {
+	name    string
+	kind    string
+	content string
+}
+
+type ddlForeignKey struct {
+	name       string
+	columns    []string
+	refSchema  string
+	refTable   string
+	refColumns []string
+}
+
+type ddlDomain struct {
+	schema   string
+	name     string
+	dataType string
+	size     dbmodel.Size
+}
+
+func newDDLSchema(config *Config) *ddlSchema {
+	return &ddlSchema{
+		driver:        config.Driver,
+		defaultSchema: ddlDefaultSchema(config),
+		tables:        make(map[string]*ddlTable),
+		domains:       make(map[string]*ddlDomain),
+	}
+}
+
+// ddlDefaultSchema returns schema of objects whose name is not qualified in DDL.
+func ddlDefaultSchema(config *Config) string {
+	switch config.Driver {
+	case "postgres":
+		return "public"
+	case "mysql":
+		if config.Schema == "" {
+			return config.Database
+		}
+	case "sqlite3":
+		return sqliteSchemaName(config.Schema)
+	}
+	return config.Schema
+}
+
+func ddlKey(schema string, name string) string {
+	return schema + "." + name
+}
+
+func (s *ddlSchema) table(schema string, name string) *ddlTable {
+	return s.tables[ddlKey(schema, name)]
+}
+
+func (s *ddlSchema) addTable(tbl *ddlTable) {
+	s.tables[ddlKey(tbl.schema, tbl.name)] = tbl
+}
+
+func (s *ddlSchema) dropTable(schema string, name string) {
+	delete(s.tables, ddlKey(schema, name))
+}
+
+// renameTable renames table, and updates foreign keys that refer it.
+func (s *ddlSchema) renameTable(tbl *ddlTable, name string) {
+	delete(s.tables, ddlKey(tbl.schema, tbl.name))
+	for _, t := range s.tables {
+		for _, fk := range t.foreignKeys {
+			if fk.refSchema == tbl.schema && fk.refTable == tbl.name {
+				fk.refTable = name
+			}
+		}
+	}
+	for _, fk := range tbl.foreignKeys {
+		if fk.refSchema == tbl.schema && fk.refTable == tbl.name {
+			fk.refTable = name
+		}
+	}
+	tbl.name = name
+	s.addTable(tbl)
+}
+
+// renameColumn renames column, and updates primary key, indices and foreign keys that contain it.
+func (s *ddlSchema) renameColumn(tbl *ddlTable, oldName string, newName string) {
+	if col := tbl.column(oldName); col != nil {
+		col.name = newName
+	}
+	renameIn(tbl.primaryKey, oldName, newName)
+	for _, idx := range tbl.indices {
+		renameIn(idx.columns, oldName, newName)
+	}
+	for _, fk := range tbl.foreignKeys {
+		renameIn(fk.columns, oldName, newName)
+	}
+	for _, t := range s.tables {
+		for _, fk := range t.foreignKeys {
+			if fk.refSchema == tbl.schema && fk.refTable == tbl.name {
+				renameIn(fk.refColumns, oldName, newName)
+			}
+		}
+	}
+}
+
+func renameIn(names []string, oldName string, newName string) {
+	for i, n := range names {
+		if n == oldName {
+			names[i] = newName
+		}
+	}
+}
+
+func (t *ddlTable) column(name string) *ddlColumn {
+	for _, col := range t.columns {
+		if col.name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (t *ddlTable) dropColumn(name string) {
+	cols := make([]*ddlColumn, 0, len(t.columns))
+	for _, col := range t.columns {
+		if col.name != name {
+			cols = append(cols, col)
+		}
+	}
+	t.columns = cols
+
+	// indices and foreign keys that contain dropped column are dropped too.
+	idxs := make([]*ddlIndex, 0, len(t.indices))
+	for _, idx := range t.indices {
+		if !containsString(idx.columns, name) {
+			idxs = append(idxs, idx)
+		}
+	}
+	t.indices = idxs
+	fks := make([]*ddlForeignKey, 0, len(t.foreignKeys))
+	for _, fk := range t.foreignKeys {
+		if !containsString(fk.columns, name) {
+			fks = append(fks, fk)
+		}
+	}
+	t.foreignKeys = fks
+	if containsString(t.primaryKey, name) {
+		t.pkName = ""
+		t.primaryKey = nil
+	}
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *ddlTable) setPrimaryKey(name string, columns []string) {
+	if name == "" {
+		name = defaultConstraintName(t.name, nil, "pkey")
+	}
+	if t.pkName != "" {
+		t.dropIndex(t.pkName)
+	}
+	t.pkName = name
+	t.primaryKey = columns
+	for _, c := range columns {
+		if col := t.column(c); col != nil {
+			col.nullable = false
+		}
+	}
+	t.addIndex(&ddlIndex{name: name, columns: columns, unique: true})
+}
+
+func (t *ddlTable) addIndex(idx *ddlIndex) {
+	t.dropIndex(idx.name)
+	t.indices = append(t.indices, idx)
+}
+
+func (t *ddlTable) dropIndex(name string) bool {
+	for i, idx := range t.indices {
+		if idx.name == name {
+			t.indices = append(t.indices[:i], t.indices[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *ddlTable) addConstraint(con *ddlConstraint) {
+	t.constraints = append(t.constraints, con)
+}
+
+func (t *ddlTable) addForeignKey(fk *ddlForeignKey) {
+	t.foreignKeys = append(t.foreignKeys, fk)
+}
+
+// dropConstraint drops constraint, foreign key, unique index or primary key that has given name.
+func (t *ddlTable) dropConstraint(name string) bool {
+	for i, con := range t.constraints {
+		if con.name == name {
+			t.constraints = append(t.constraints[:i], t.constraints[i+1:]...)
+			return true
+		}
+	}
+	for i, fk := range t.foreignKeys {
+		if fk.name == name {
+			t.foreignKeys = append(t.foreignKeys[:i], t.foreignKeys[i+1:]...)
+			return true
+		}
+	}
+	if t.pkName == name {
+		t.pkName = ""
+		t.primaryKey = nil
+	}
+	return t.dropIndex(name)
+}
+
+func (t *ddlTable) primaryKeyPosition(name string) int64 {
+	for i, c := range t.primaryKey {
+		if c == name {
+			return int64(i + 1)
+		}
+	}
+	return 0
+}
+
+// build makes dbmodel tables from schema model. Tables are sorted by schema and name.
+// Indices, constraints and foreign keys are sorted by name like database catalog.
+func (s *ddlSchema) build() []*dbmodel.Table {
+	keys := make([]string, 0, len(s.tables))
+	for k := range s.tables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tables := make([]*dbmodel.Table, 0, len(keys))
+	tblMap := make(map[string]*dbmodel.Table)
+	colMap := make(map[string]*dbmodel.Column)
+	for _, k := range keys {
+		dt := s.tables[k]
+		tbl := dbmodel.NewTable(dt.schema, dt.name, dt.comment)
+		for _, dc := range dt.columns {
+			col := dbmodel.NewColumn(dt.schema, dt.name, dc.name, dc.comment, dc.dataType, dc.size, dc.nullable, dc.defVal, dt.primaryKeyPosition(dc.name))
+			tbl.AddColumn(&col)
+			colMap[columnKey(dt.schema, dt.name, dc.name)] = &col
+		}
+
+		idxs := append([]*ddlIndex{}, dt.indices...)
+		sort.Slice(idxs, func(i, j int) bool { return idxs[i].name < idxs[j].name })
+		for _, di := range idxs {
+			idx := dbmodel.NewIndex(dt.schema, dt.name, di.name, di.unique)
+			for _, c := range di.columns {
+				if col, ok := colMap[columnKey(dt.schema, dt.name, c)]; ok {
+					idx.AddColumn(col)
+				}
+			}
+			tbl.AddIndex(&idx)
+		}
+
+		cons := append([]*ddlConstraint{}, dt.constraints...)
+		sort.Slice(cons, func(i, j int) bool { return cons[i].name < cons[j].name })
+		for _, dc := range cons {
+			con := dbmodel.NewConstraint(dt.schema, dt.name, dc.name, dc.kind, dc.content)
+			tbl.AddConstraint(&con)
+		}
+
+		tables = append(tables, &tbl)
+		tblMap[k] = &tbl
+	}
+
+	type ownedFk struct {
+		tbl *ddlTable
+		fk  *ddlForeignKey
+	}
+	fks := make([]ownedFk, 0)
+	for _, k := range keys {
+		for _, fk := range s.tables[k].foreignKeys {
+			fks = append(fks, ownedFk{tbl: s.tables[k], fk: fk})
+		}
+	}
+	sort.SliceStable(fks, func(i, j int) bool { return fks[i].fk.name < fks[j].fk.name })
+	for _, of := range fks {
+		dt, dfk := of.tbl, of.fk
+		refCols := dfk.refColumns
+		if len(refCols) == 0 {
+			if rt := s.table(dfk.refSchema, dfk.refTable); rt != nil {
+				refCols = rt.primaryKey
+			}
+		}
+		fk := dbmodel.NewForeignKey(dt.schema, dt.name, dfk.name)
+		for i, c := range dfk.columns {
+			if i >= len(refCols) {
+				break
+			}
+			ref := dbmodel.NewColumnReference(findOrStubColumn(colMap, dt.schema, dt.name, c), findOrStubColumn(colMap, dfk.refSchema, dfk.refTable, refCols[i]))
+			fk.AddColumnReference(&ref)
+		}
+		if len(fk.ColumnReferences()) == 0 {
+			continue
+		}
+		tblMap[ddlKey(dt.schema, dt.name)].AddForeignKey(&fk)
+		if rt, ok := tblMap[ddlKey(dfk.refSchema, dfk.refTable)]; ok {
+			rt.AddReferencedKey(&fk)
+		}
+	}
+	return tables
+}
+
+// defaultConstraintName returns constraint name that PostgreSQL makes when name is omitted.
+// Like makeObjectName of PostgreSQL, longer one of table and columns is shortened until name fits postgresNameLength.
+func defaultConstraintName(table string, columns []string, suffix string) string {
+	cols := strings.Join(columns, "_")
+	available := postgresNameLength - len(suffix) - 1
+	if cols != "" {
+		available--
+	}
+	tableLen, colsLen := len(table), len(cols)
+	for tableLen+colsLen > available {
+		if tableLen > colsLen {
+			tableLen--
+		} else {
+			colsLen--
+		}
+	}
+	parts := []string{clipIdentifier(table, tableLen)}
+	if cols != "" {
+		parts = append(parts, clipIdentifier(cols, colsLen))
+	}
+	return strings.Join(append(parts, suffix), "_")
+}
+
+// clipIdentifier returns head of name within n bytes. Multibyte character is not split.
+func clipIdentifier(name string, n int) string {
+	if len(name) <= n {
+		return name
+	}
+	for n > 0 && !utf8.RuneStart(name[n]) {
+		n--
+	}
+	return name[:n]
+}
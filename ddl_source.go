@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// ddlSource loads table definitions from DDL file without database connection.
+// driver in config is used for dialect of DDL, and connection settings are ignored.
+type ddlSource struct {
+	schema *ddlSchema
+	tables []*dbmodel.Table
+}
+
+func newDDLSource(config *Config, path string, warn io.Writer) (*ddlSource, error) {
+	rpath, err := resolvePath(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(rpath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newDDLSchema(config)
+	for _, w := range s.applySQL(filepath.Base(rpath), string(content)) {
+		fmt.Fprintln(warn, "WARNING:", w)
+	}
+	return &ddlSource{schema: s, tables: s.build()}, nil
+}
+
+// newMigrationSource applies up-migrations in directory in order of version.
+// When until is given, migrations whose version is greater than it are not applied.
+func newMigrationSource(config *Config, dir string, until string, warn io.Writer) (*ddlSource, error) {
+	rdir, err := resolvePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	migs, warnings, err := loadMigrations(rdir)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(warn, "WARNING:", w)
+	}
+	if until != "" && !containsVersion(migs, until) {
+		return nil, fmt.Errorf("Migration version '%s' is not found.", until)
+	}
+
+	s := newDDLSchema(config)
+	for _, m := range migs {
+		if until != "" && compareVersion(m.version, until) > 0 {
+			break
+		}
+		text, err := m.upSQL()
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range s.applySQL(filepath.Base(m.file), text) {
+			fmt.Fprintln(warn, "WARNING:", w)
+		}
+	}
+	return &ddlSource{schema: s, tables: s.build()}, nil
+}
+
+func containsVersion(migs []*migration, version string) bool {
+	for _, m := range migs {
+		if compareVersion(m.version, version) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ddlSource) AllTableNames(schema string) ([]*dbmodel.Table, error) {
+	return s.AllTables(schema)
+}
+
+func (s *ddlSource) AllTables(schema string) ([]*dbmodel.Table, error) {
+	schema = s.schemaName(schema)
+	tables := make([]*dbmodel.Table, 0)
+	for _, tbl := range s.tables {
+		if tbl.Schema() == schema {
+			tables = append(tables, tbl)
+		}
+	}
+	return tables, nil
+}
+
+func (s *ddlSource) Table(schema string, name string, all bool) (*dbmodel.Table, error) {
+	schema = s.schemaName(schema)
+	for _, tbl := range s.tables {
+		if tbl.Schema() != schema || tbl.Name() != name {
+			continue
+		}
+		if all {
+			return tbl, nil
+		}
+		return columnsOnly(tbl), nil
+	}
+	return nil, fmt.Errorf("Table '%s' is not found.", name)
+}
+
+func (s *ddlSource) Close() {
+}
+
+func (s *ddlSource) schemaName(schema string) string {
+	if schema == "" {
+		return s.schema.defaultSchema
+	}
+	return schema
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCmdShowWithDDL(t *testing.T) {
+	initShowOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-ddl")
+	showOpt.showAll = true
+	showOpt.ddlFile = "test/create_postgres_resources.sql"
+	stat := cmdShow.Run([]string{"sales_order_header"})
+	if stat != 0 {
+		t.Error("Show subcommand should finish normally.")
+	}
+	expected := strings.TrimSpace(`
++----+---------------------------+----------------------+-------+------+------------------------------------------------------------+---------------------------------------------------------------------------------------------------------------+
+| PK |           NAME            |         TYPE         | SIZE  | NULL |                          DEFAULT                           |                                                    COMMENT                                                    |
++----+---------------------------+----------------------+-------+------+------------------------------------------------------------+---------------------------------------------------------------------------------------------------------------+
+|  1 | sales_order_id            | int4                 | 32, 0 | NO   | nextval('sales_order_header_sales_order_id_seq'::regclass) | Primary key.                                                                                                  |
+|    | revision_number           | int2                 | 16, 0 | NO   |                                                          0 | Incremental number to track changes to the sales order over time.                                             |
+|    | order_date                | timestamp            |     6 | NO   | now()                                                      | Dates the sales order was created.                                                                            |
+|    | due_date                  | timestamp            |     6 | NO   |                                                            | Date the order is due to the customer.                                                                        |
+|    | ship_date                 | timestamp            |     6 |      |                                                            | Date the order was shipped to the customer.                                                                   |
+|    | status                    | int2                 | 16, 0 | NO   |                                                          1 | Order current status. 1 = In process; 2 = Approved; 3 = Backordered; 4 = Rejected; 5 = Shipped; 6 = Cancelled |
+|    | online_order_flag         | public.Flag          |       | NO   | true                                                       | 0 = Order placed by sales person. 1 = Order placed online by customer.                                        |
+|    | purchase_order_number     | public.OrderNumber   |    25 |      |                                                            | Customer purchase order number reference.                                                                     |
+|    | account_number            | public.AccountNumber |    15 |      |                                                            | Financial accounting number reference.                                                                        |
+|    | customer_id               | int4                 | 32, 0 | NO   |                                                            | Customer identification number. Foreign key to customer.business_entity_id.                                   |
+|    | sales_person_id           | int4                 | 32, 0 |      |                                                            | Sales person who created the sales order. Foreign key to sales_person.business_entity_id.                     |
+|    | territory_id              | int4                 | 32, 0 |      |                                                            | Territory in which the sale was made. Foreign key to sales_territory.sales_territory_id.                      |
+|    | bill_to_address_id        | int4                 | 32, 0 | NO   |                                                            | Customer billing address. Foreign key to address.address_id.                                                  |
+|    | ship_to_address_id        | int4                 | 32, 0 | NO   |                                                            | Customer shipping address. Foreign key to address.address_id.                                                 |
+|    | ship_method_id            | int4                 | 32, 0 | NO   |                                                            | Shipping method. Foreign key to ship_method.ship_method_id.                                                   |
+|    | credit_card_id            | int4                 | 32, 0 |      |                                                            | Credit card identification number. Foreign key to credit_card.credit_card_id.                                 |
+|    | credit_card_approval_code | varchar              |    15 |      |                                                            | Approval code provided by the credit card company.                                                            |
+|    | currency_rate_id          | int4                 | 32, 0 |      |                                                            | Currency exchange rate used. Foreign key to currency_rate.currency_rate_id.                                   |
+|    | sub_total                 | numeric              |       | NO   |                                                       0.00 | Sales subtotal. Computed as SUM(sales_order_detail.line_total)for the appropriate sales_order_id.             |
+|    | tax_amt                   | numeric              |       | NO   |                                                       0.00 | Tax amount.                                                                                                   |
+|    | freight                   | numeric              |       | NO   |                                                       0.00 | Shipping cost.                                                                                                |
+|    | total_due                 | numeric              |       |      |                                                            | Total due from customer. Computed as subtotal + tax_amt + freight.                                            |
+|    | comment                   | varchar              |   128 |      |                                                            | Sales representative comments.                                                                                |
+|    | rowguid                   | uuid                 |       | NO   | uuid_generate_v1()                                         |                                                                                                               |
+|    | modified_date             | timestamp            |     6 | NO   | now()                                                      |                                                                                                               |
++----+---------------------------+----------------------+-------+------+------------------------------------------------------------+---------------------------------------------------------------------------------------------------------------+
+
+### Indices
++--------------------------------------+----------------+--------+
+|                 NAME                 |    COLUMNS     | UNIQUE |
++--------------------------------------+----------------+--------+
+| pk_sales_order_header_sales_order_id | sales_order_id | YES    |
++--------------------------------------+----------------+--------+
+
+### Constraints
++---------------------------------+-------+----------------------------------------------------+
+|              NAME               | KIND  |                      CONTENT                       |
++---------------------------------+-------+----------------------------------------------------+
+| ck_sales_order_header_due_date  | CHECK | (due_date >= order_date)                           |
+| ck_sales_order_header_freight   | CHECK | (freight >= 0.00)                                  |
+| ck_sales_order_header_ship_date | CHECK | ((ship_date >= order_date) OR (ship_date IS NULL)) |
+| ck_sales_order_header_status    | CHECK | (status BETWEEN 0 AND 8)                           |
+| ck_sales_order_header_sub_total | CHECK | (sub_total >= 0.00)                                |
+| ck_sales_order_header_tax_amt   | CHECK | (tax_amt >= 0.00)                                  |
++---------------------------------+-------+----------------------------------------------------+
+
+### Foreign keys
++------------------------------------------------------+--------------------+------------------------+--------------------+
+|                         NAME                         |      COLUMNS       |     FOREIGN TABLE      |  FOREIGN COLUMNS   |
++------------------------------------------------------+--------------------+------------------------+--------------------+
+| fk_sales_order_header_address_bill_to_address_id     | bill_to_address_id | person.address         | address_id         |
+| fk_sales_order_header_address_ship_to_address_id     | ship_to_address_id | person.address         | address_id         |
+| fk_sales_order_header_credit_card_credit_card_id     | credit_card_id     | credit_card            | credit_card_id     |
+| fk_sales_order_header_currency_rate_currency_rate_id | currency_rate_id   | currency_rate          | currency_rate_id   |
+| fk_sales_order_header_customer_customer_id           | customer_id        | customer               | customer_id        |
+| fk_sales_order_header_sales_person_sales_person_id   | sales_person_id    | sales_person           | business_entity_id |
+| fk_sales_order_header_sales_territory_territory_id   | territory_id       | sales_territory        | territory_id       |
+| fk_sales_order_header_ship_method_ship_method_id     | ship_method_id     | purchasing.ship_method | ship_method_id     |
++------------------------------------------------------+--------------------+------------------------+--------------------+
+
+### Referenced keys
++-----------------------------------------------------------------+---------------------------------+----------------+----------------+
+|                              NAME                               |          SOURCE TABLE           | SOURCE COLUMNS |    COLUMNS     |
++-----------------------------------------------------------------+---------------------------------+----------------+----------------+
+| fk_sales_order_detail_sales_order_header_sales_order_id         | sales_order_detail              | sales_order_id | sales_order_id |
+| fk_sales_order_header_sales_reason_sales_order_header_sales_ord | sales_order_header_sales_reason | sales_order_id | sales_order_id |
++-----------------------------------------------------------------+---------------------------------+----------------+----------------+`)
+	if actual := strings.TrimSpace(buf.String()); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdShowWithDDLWarnings(t *testing.T) {
+	initShowOpt()
+	ebuf := &bytes.Buffer{}
+	o.out = &bytes.Buffer{}
+	o.err = ebuf
+	setupTestConfigFile("tablarian-ddl")
+	showOpt.ddlFile = "test/create_postgres_resources.sql"
+	stat := cmdShow.Run([]string{"sales_order_header"})
+	if stat != 0 {
+		t.Error("Show subcommand should finish normally.")
+	}
+	expected := "WARNING: create_postgres_resources.sql:3: 'CREATE EXTENSION' is not supported. (skipped)"
+	if actual := strings.Split(ebuf.String(), "\n")[0]; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdShowWithDDLNotFound(t *testing.T) {
+	initShowOpt()
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-ddl")
+	showOpt.ddlFile = "test/not_found.sql"
+	stat := cmdShow.Run([]string{"sales_order_header"})
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+}
+
+func TestCmdIndexWithDDL(t *testing.T) {
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-ddl")
+	idxOpt.withoutTableComment = true
+	idxOpt.ddlFile = "test/create_postgres_resources.sql"
+	defer func() { idxOpt.ddlFile = "" }()
+	stat := cmdIndex.Run([]string{})
+	if stat != 0 {
+		t.Error("Index subcommand should finish normally.")
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 19 {
+		t.Errorf("expected: %v, actual: %v", 19, len(lines))
+	}
+	if lines[0] != "country_region_currency" {
+		t.Errorf("expected: %v, actual: %v", "country_region_currency", lines[0])
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+var diagramAliasPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// DiagramConfig is setting of ER diagram.
+// Diagram has all tables by default, or is limited to Tables and tables within Hops from Focus.
+// SVG enables SVG diagrams that are written with markdown or html.
+type DiagramConfig struct {
+	Tables   []string `json:"tables"`
+	Focus    string   `json:"focus"`
+	Hops     int      `json:"hops"`
+	Markdown string   `json:"markdown"`
+	SVG      bool     `json:"svg"`
+}
+
+// diagramRelation is relationship between tables made from a foreign key.
+// parentOptional is true when columns of foreign key are nullable,
+// and childMany is false when columns of foreign key are unique.
+type diagramRelation struct {
+	fk             *dbmodel.ForeignKey
+	parent         *dbmodel.Table
+	child          *dbmodel.Table
+	parentOptional bool
+	childMany      bool
+}
+
+// diagramTables returns tables in diagram. Order of tables is kept.
+func diagramTables(tables []*dbmodel.Table, dc DiagramConfig) ([]*dbmodel.Table, error) {
+	if len(dc.Tables) == 0 && dc.Focus == "" {
+		return tables, nil
+	}
+	tblMap := make(map[string]*dbmodel.Table)
+	for _, tbl := range tables {
+		tblMap[tbl.Name()] = tbl
+	}
+	selected := make(map[string]bool)
+	for _, name := range dc.Tables {
+		if _, ok := tblMap[name]; !ok {
+			return nil, fmt.Errorf("Table '%s' is not found.", name)
+		}
+		selected[name] = true
+	}
+	if dc.Focus != "" {
+		if _, ok := tblMap[dc.Focus]; !ok {
+			return nil, fmt.Errorf("Table '%s' is not found.", dc.Focus)
+		}
+		hops := dc.Hops
+		if hops <= 0 {
+			hops = 1
+		}
+		selected[dc.Focus] = true
+		current := []string{dc.Focus}
+		for i := 0; i < hops; i++ {
+			next := make([]string, 0)
+			for _, name := range current {
+				for _, n := range neighborTables(tblMap[name]) {
+					if _, ok := tblMap[n]; ok && !selected[n] {
+						selected[n] = true
+						next = append(next, n)
+					}
+				}
+			}
+			current = next
+		}
+	}
+
+	result := make([]*dbmodel.Table, 0, len(selected))
+	for _, tbl := range tables {
+		if selected[tbl.Name()] {
+			result = append(result, tbl)
+		}
+	}
+	return result, nil
+}
+
+func containsTable(tables []*dbmodel.Table, tbl *dbmodel.Table) bool {
+	for _, t := range tables {
+		if t == tbl {
+			return true
+		}
+	}
+	return false
+}
+
+// neighborTables returns names of tables that reference table or are referenced by table.
+func neighborTables(tbl *dbmodel.Table) []string {
+	names := make([]string, 0)
+	for _, fk := range tbl.ForeignKeys() {
+		if refs := fk.ColumnReferences(); len(refs) > 0 {
+			names = append(names, refs[0].To().TableName())
+		}
+	}
+	for _, rk := range tbl.ReferencedKeys() {
+		names = append(names, rk.TableName())
+	}
+	return names
+}
+
+// diagramRelations returns relations between given tables.
+func diagramRelations(tables []*dbmodel.Table) []diagramRelation {
+	tblMap := make(map[string]*dbmodel.Table)
+	for _, tbl := range tables {
+		tblMap[ddlKey(tbl.Schema(), tbl.Name())] = tbl
+	}
+	rels := make([]diagramRelation, 0)
+	for _, tbl := range tables {
+		for _, fk := range tbl.ForeignKeys() {
+			refs := fk.ColumnReferences()
+			if len(refs) == 0 {
+				continue
+			}
+			parent, ok := tblMap[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]
+			if !ok {
+				continue
+			}
+			rel := diagramRelation{fk: fk, parent: parent, child: tbl, childMany: !isUniqueColumns(tbl, fk)}
+			for _, ref := range refs {
+				if col := findColumn(tbl, ref.From().Name()); col != nil && col.IsNullable() {
+					rel.parentOptional = true
+				}
+			}
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+// isUniqueColumns returns true when columns of foreign key are primary key or have unique index.
+func isUniqueColumns(tbl *dbmodel.Table, fk *dbmodel.ForeignKey) bool {
+	names := make([]string, 0, len(fk.ColumnReferences()))
+	for _, ref := range fk.ColumnReferences() {
+		names = append(names, ref.From().Name())
+	}
+	pk := make([]string, 0)
+	for _, col := range tbl.Columns() {
+		if col.PrimaryKeyPosition() > 0 {
+			pk = append(pk, col.Name())
+		}
+	}
+	if sameStringSet(pk, names) {
+		return true
+	}
+	for _, idx := range tbl.Indices() {
+		if !idx.IsUnique() {
+			continue
+		}
+		cols := make([]string, 0, len(idx.Columns()))
+		for _, col := range idx.Columns() {
+			cols = append(cols, col.Name())
+		}
+		if sameStringSet(cols, names) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameStringSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, s := range a {
+		if !containsString(b, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func findColumn(tbl *dbmodel.Table, name string) *dbmodel.Column {
+	for _, col := range tbl.Columns() {
+		if col.Name() == name {
+			return col
+		}
+	}
+	return nil
+}
+
+// splitPrimaryKeyColumns returns primary key columns in order of position and other columns.
+func splitPrimaryKeyColumns(tbl *dbmodel.Table) ([]*dbmodel.Column, []*dbmodel.Column) {
+	pks := make([]*dbmodel.Column, 0)
+	others := make([]*dbmodel.Column, 0)
+	for _, col := range tbl.Columns() {
+		if col.PrimaryKeyPosition() > 0 {
+			pks = append(pks, col)
+		} else {
+			others = append(others, col)
+		}
+	}
+	sort.SliceStable(pks, func(i, j int) bool { return pks[i].PrimaryKeyPosition() < pks[j].PrimaryKeyPosition() })
+	return pks, others
+}
+
+// isForeignKeyColumn returns true when column is a part of foreign key of table.
+func isForeignKeyColumn(tbl *dbmodel.Table, col *dbmodel.Column) bool {
+	for _, fk := range tbl.ForeignKeys() {
+		for _, ref := range fk.ColumnReferences() {
+			if ref.From().Name() == col.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// diagramColumnType returns data type with size that converter prints.
+func diagramColumnType(conv Converter, col *dbmodel.Column) string {
+	values := conv.ConvertColumn(col)
+	if values[3] == "" {
+		return values[2]
+	}
+	return fmt.Sprintf("%s(%s)", values[2], values[3])
+}
+
+// diagramAlias returns identifier that diagram languages accept.
+func diagramAlias(name string) string {
+	return diagramAliasPattern.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+type diffOption struct {
+	prettyPrint      bool
+	format           string
+	noColor          bool
+	sql              bool
+	allowDestructive bool
+}
+
+const (
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorReset  = "\x1b[0m"
+)
+
+var (
+	cmdDiff = &Command{
+		Run:       runDiff,
+		UsageLine: "diff FROM TO",
+		Short:     "Print differences between two schemas.",
+		Long: `Print differences of tables, columns, indices, constraints and foreign keys between two schemas.
+FROM and TO are config files or snapshot files made by dump command.
+if file path starts with '@', it is treated as absolute file path.
+
+Exit code is 0 when schemas are same, 1 when differences exist and 2 when error occurred.
+
+Options:
+    -p, --pretty
+        convert data type to usually name.
+        this option is author's personal option. (PostgreSQL and MySQL)
+
+    -f FORMAT, --format FORMAT
+        output format.
+        formats:
+            text (default)
+            markdown
+            json
+
+    --no-color
+        not colorize text output. text output is not colorized when it is not terminal.
+
+    --sql
+        print PostgreSQL statements that migrate FROM schema to TO schema instead of differences.
+        statements that drop tables or columns or narrow types of columns are commented out.
+
+    --allow-destructive
+        not comment out statements that drop tables or columns or narrow types of columns. (with --sql)
+	`,
+	}
+	diffOpt = diffOption{}
+)
+
+func init() {
+	cmdDiff.Flag.BoolVar(&diffOpt.prettyPrint, "pretty", false, "Pretty print")
+	cmdDiff.Flag.BoolVar(&diffOpt.prettyPrint, "p", false, "Pretty print")
+	cmdDiff.Flag.StringVar(&diffOpt.format, "format", "text", "Output format")
+	cmdDiff.Flag.StringVar(&diffOpt.format, "f", "text", "Output format")
+	cmdDiff.Flag.BoolVar(&diffOpt.noColor, "no-color", false, "Not colorize output")
+	cmdDiff.Flag.BoolVar(&diffOpt.sql, "sql", false, "Print migration SQL")
+	cmdDiff.Flag.BoolVar(&diffOpt.allowDestructive, "allow-destructive", false, "Enable destructive statements")
+}
+
+// runDiff executes diff command and return exit code.
+func runDiff(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(o.err, "require two config or snapshot files as arguments.")
+		return 2
+	}
+	from, driver, err := loadDiffTables(args[0])
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 2
+	}
+	to, _, err := loadDiffTables(args[1])
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 2
+	}
+
+	d := diffSchemas(from, to, findConverter(diffOpt.prettyPrint, driver))
+	d.From = args[0]
+	d.To = args[1]
+	var out []byte
+	switch {
+	case diffOpt.sql:
+		if driver != "postgres" {
+			err = fmt.Errorf("--sql supports only postgres driver.")
+			break
+		}
+		out = diffToSQL(d, diffOpt.allowDestructive)
+	case diffOpt.format == "text":
+		out = diffToText(d, !diffOpt.noColor && isTerminal(o.out))
+	case diffOpt.format == "markdown":
+		out = diffToMarkdown(d)
+	case diffOpt.format == "json":
+		out, err = diffToJSON(d)
+	default:
+		err = fmt.Errorf("Format '%s' is invalid format.", diffOpt.format)
+	}
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 2
+	}
+	o.out.Write(out)
+
+	if d.hasDifferences() {
+		return 1
+	}
+	return 0
+}
+
+// loadDiffTables loads all tables from snapshot file or database of config file.
+// It returns driver name too.
+func loadDiffTables(path string) ([]*dbmodel.Table, string, error) {
+	rpath, err := resolvePath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := ioutil.ReadFile(rpath)
+	if err != nil {
+		return nil, "", err
+	}
+	probe := map[string]interface{}{}
+	if err = json.Unmarshal(content, &probe); err != nil {
+		return nil, "", err
+	}
+
+	if _, ok := probe["format_version"]; ok {
+		snap, err := loadSnapshot(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return snap.build(), snap.Driver, nil
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+	src, err := findSource(cfg, baseOption{})
+	if err != nil {
+		return nil, "", err
+	}
+	defer src.Close()
+	tables, err := src.AllTables(cfg.Schema)
+	return tables, cfg.Driver, err
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func diffMark(status string) string {
+	switch status {
+	case diffAdded:
+		return "+"
+	case diffRemoved:
+		return "-"
+	}
+	return "~"
+}
+
+func diffColor(status string) string {
+	switch status {
+	case diffAdded:
+		return colorGreen
+	case diffRemoved:
+		return colorRed
+	}
+	return colorYellow
+}
+
+func diffToText(d *schemaDiff, color bool) []byte {
+	buf := &bytes.Buffer{}
+	if !d.hasDifferences() {
+		fmt.Fprintln(buf, "No differences.")
+		return buf.Bytes()
+	}
+	line := func(indent string, status string, text string) {
+		if color {
+			fmt.Fprintf(buf, "%s%s%s %s%s\n", indent, diffColor(status), diffMark(status), text, colorReset)
+		} else {
+			fmt.Fprintf(buf, "%s%s %s\n", indent, diffMark(status), text)
+		}
+	}
+	for _, td := range d.Tables {
+		line("", td.Status, td.Name)
+		for _, c := range td.Changes {
+			fmt.Fprintf(buf, "      %s: %s -> %s\n", c.Attribute, c.From, c.To)
+		}
+		for _, g := range td.groups() {
+			for _, ed := range g.diffs {
+				line("    ", ed.Status, g.kind+" "+ed.Name)
+				for _, c := range ed.Changes {
+					fmt.Fprintf(buf, "          %s: %s -> %s\n", c.Attribute, c.From, c.To)
+				}
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func diffToMarkdown(d *schemaDiff) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "# Schema differences")
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "`%s` -> `%s`\n", d.From, d.To)
+	if !d.hasDifferences() {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "No differences.")
+		return buf.Bytes()
+	}
+	for _, td := range d.Tables {
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "## %s (%s)\n", td.Name, td.Status)
+		if td.Status != diffChanged {
+			continue
+		}
+		fmt.Fprintln(buf)
+		w := newMdTableWriter(buf)
+		w.SetHeader([]string{"OBJECT", "NAME", "STATUS", "ATTRIBUTE", "FROM", "TO"})
+		for _, c := range td.Changes {
+			w.Append([]string{"table", td.Name, td.Status, c.Attribute, c.From, c.To})
+		}
+		for _, g := range td.groups() {
+			for _, ed := range g.diffs {
+				if len(ed.Changes) == 0 {
+					w.Append([]string{g.kind, ed.Name, ed.Status, "", "", ""})
+				}
+				for _, c := range ed.Changes {
+					w.Append([]string{g.kind, ed.Name, ed.Status, c.Attribute, c.From, c.To})
+				}
+			}
+		}
+		w.Render()
+	}
+	return buf.Bytes()
+}
+
+func diffToJSON(d *schemaDiff) ([]byte, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+type elementDiffGroup struct {
+	kind  string
+	diffs []*elementDiff
+}
+
+func (td *tableDiff) groups() []elementDiffGroup {
+	return []elementDiffGroup{
+		{"column", td.Columns},
+		{"index", td.Indices},
+		{"constraint", td.Constraints},
+		{"foreign key", td.ForeignKeys},
+	}
+}
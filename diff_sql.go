@@ -0,0 +1,528 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+var (
+	plainIdentPattern = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+	// postgresReservedWords are words that can not be used as identifier without quoting.
+	postgresReservedWords = []string{
+		"all", "analyse", "analyze", "and", "any", "array", "as", "asc", "asymmetric", "both", "case", "cast", "check",
+		"collate", "column", "constraint", "create", "current_catalog", "current_date", "current_role", "current_time",
+		"current_timestamp", "current_user", "default", "deferrable", "desc", "distinct", "do", "else", "end", "except",
+		"false", "fetch", "for", "foreign", "from", "grant", "group", "having", "in", "initially", "intersect", "into",
+		"lateral", "leading", "limit", "localtime", "localtimestamp", "not", "null", "offset", "on", "only", "or", "order",
+		"placing", "primary", "references", "returning", "select", "session_user", "some", "symmetric", "table", "then",
+		"to", "trailing", "true", "union", "unique", "user", "using", "variadic", "when", "where", "window", "with",
+	}
+	// sqlWideningTypes are types that values of the type can be converted to without loss.
+	sqlWideningTypes = map[string][]string{
+		"int2":    {"int4", "int8", "numeric"},
+		"int4":    {"int8", "numeric"},
+		"int8":    {"numeric"},
+		"float4":  {"float8"},
+		"varchar": {"text"},
+	}
+)
+
+// sqlStatement is a statement of migration SQL.
+// Destructive statement drops data, so it is commented out unless it is allowed.
+type sqlStatement struct {
+	text        string
+	destructive bool
+}
+
+// sqlMigration keeps statements by phase. Phases are printed in order of fields.
+type sqlMigration struct {
+	dropForeignKeys []sqlStatement
+	dropIndices     []sqlStatement
+	createTables    []sqlStatement
+	alterColumns    []sqlStatement
+	createIndices   []sqlStatement
+	dropColumns     []sqlStatement
+	dropTables      []sqlStatement
+	addForeignKeys  []sqlStatement
+}
+
+func (m *sqlMigration) phases() [][]sqlStatement {
+	return [][]sqlStatement{m.dropForeignKeys, m.dropIndices, m.createTables, m.alterColumns, m.createIndices, m.dropColumns, m.dropTables, m.addForeignKeys}
+}
+
+// diffToSQL makes PostgreSQL statements that migrate FROM schema to TO schema.
+// Foreign keys are dropped first and added last, and tables are created and dropped in order of references.
+func diffToSQL(d *schemaDiff, allowDestructive bool) []byte {
+	buf := &bytes.Buffer{}
+	if !d.hasDifferences() {
+		fmt.Fprintln(buf, "-- No differences.")
+		return buf.Bytes()
+	}
+
+	m := &sqlMigration{}
+	added := make([]*dbmodel.Table, 0)
+	removed := make([]*dbmodel.Table, 0)
+	for _, td := range d.Tables {
+		switch td.Status {
+		case diffAdded:
+			added = append(added, td.to)
+		case diffRemoved:
+			removed = append(removed, td.from)
+		default:
+			m.addTableChanges(td)
+		}
+	}
+	m.addForeignKeysOfDroppedKeys(d.Tables)
+	for _, tbl := range sortTablesByReference(added, referencedTables) {
+		m.addCreateTable(tbl)
+	}
+	for _, tbl := range sortTablesByReference(removed, referencingTables) {
+		m.dropTables = append(m.dropTables, sqlStatement{text: "DROP TABLE " + qualifiedTableName(tbl.Schema(), tbl.Name()), destructive: true})
+	}
+
+	stmts := make([]sqlStatement, 0)
+	for _, p := range m.phases() {
+		stmts = append(stmts, p...)
+	}
+	for _, stmt := range stmts {
+		if stmt.destructive && !allowDestructive {
+			fmt.Fprintln(buf, "-- Destructive statements are commented out. Use --allow-destructive to enable them.")
+			fmt.Fprintln(buf)
+			break
+		}
+	}
+	for _, stmt := range stmts {
+		text := stmt.text + ";"
+		if stmt.destructive && !allowDestructive {
+			text = "-- " + strings.Replace(text, "\n", "\n-- ", -1)
+		}
+		fmt.Fprintln(buf, text)
+	}
+	return buf.Bytes()
+}
+
+func (m *sqlMigration) addCreateTable(tbl *dbmodel.Table) {
+	name := qualifiedTableName(tbl.Schema(), tbl.Name())
+	defs := make([]string, 0, len(tbl.Columns()))
+	for _, col := range tbl.Columns() {
+		defs = append(defs, "    "+sqlColumnDefinition(col))
+	}
+	indices := make([]*dbmodel.Index, 0, len(tbl.Indices()))
+	for _, idx := range tbl.Indices() {
+		if isPrimaryKeyIndex(tbl, idx) {
+			defs = append(defs, fmt.Sprintf("    CONSTRAINT %s PRIMARY KEY (%s)", quoteIdent(idx.Name()), sqlIndexColumns(idx)))
+		} else if isUniqueConstraintIndex(tbl, idx) {
+			defs = append(defs, fmt.Sprintf("    CONSTRAINT %s UNIQUE (%s)", quoteIdent(idx.Name()), sqlIndexColumns(idx)))
+		} else {
+			indices = append(indices, idx)
+		}
+	}
+	for _, con := range tbl.Constraints() {
+		defs = append(defs, fmt.Sprintf("    CONSTRAINT %s %s %s", quoteIdent(con.Name()), con.Kind(), con.Content()))
+	}
+	m.createTables = append(m.createTables, sqlStatement{text: fmt.Sprintf("CREATE TABLE %s (\n%s\n)", name, strings.Join(defs, ",\n"))})
+	if tbl.Comment() != "" {
+		m.createTables = append(m.createTables, sqlStatement{text: fmt.Sprintf("COMMENT ON TABLE %s IS %s", name, sqlCommentText(tbl.Comment()))})
+	}
+	for _, col := range tbl.Columns() {
+		if col.Comment() != "" {
+			m.createTables = append(m.createTables, sqlStatement{text: fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", name, quoteIdent(col.Name()), sqlCommentText(col.Comment()))})
+		}
+	}
+	for _, idx := range indices {
+		m.createTables = append(m.createTables, sqlStatement{text: sqlCreateIndex(tbl, idx)})
+	}
+	for _, fk := range tbl.ForeignKeys() {
+		m.addForeignKeys = append(m.addForeignKeys, sqlStatement{text: sqlAddForeignKey(fk)})
+	}
+}
+
+func (m *sqlMigration) addTableChanges(td *tableDiff) {
+	name := qualifiedTableName(td.to.Schema(), td.to.Name())
+	for _, c := range td.Changes {
+		if c.Attribute == "comment" {
+			m.alterColumns = append(m.alterColumns, sqlStatement{text: fmt.Sprintf("COMMENT ON TABLE %s IS %s", name, sqlCommentText(td.to.Comment()))})
+		}
+	}
+
+	for _, ed := range td.Columns {
+		switch ed.Status {
+		case diffAdded:
+			col := ed.to.(*dbmodel.Column)
+			m.alterColumns = append(m.alterColumns, sqlStatement{text: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", name, sqlColumnDefinition(col))})
+			if col.Comment() != "" {
+				m.alterColumns = append(m.alterColumns, sqlStatement{text: fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", name, quoteIdent(col.Name()), sqlCommentText(col.Comment()))})
+			}
+		case diffRemoved:
+			col := ed.from.(*dbmodel.Column)
+			m.dropColumns = append(m.dropColumns, sqlStatement{text: fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", name, quoteIdent(col.Name())), destructive: true})
+		default:
+			m.addColumnChanges(name, ed)
+		}
+	}
+
+	for _, ed := range td.Indices {
+		if ed.from != nil {
+			m.dropIndices = append(m.dropIndices, sqlStatement{text: sqlDropIndex(td.from, ed.from.(*dbmodel.Index))})
+		}
+		if ed.to != nil {
+			idx := ed.to.(*dbmodel.Index)
+			text := sqlCreateIndex(td.to, idx)
+			if isPrimaryKeyIndex(td.to, idx) {
+				text = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s)", name, quoteIdent(idx.Name()), sqlIndexColumns(idx))
+			} else if isUniqueConstraintIndex(td.to, idx) {
+				text = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", name, quoteIdent(idx.Name()), sqlIndexColumns(idx))
+			}
+			m.createIndices = append(m.createIndices, sqlStatement{text: text})
+		}
+	}
+
+	for _, ed := range td.Constraints {
+		if ed.from != nil {
+			con := ed.from.(*dbmodel.Constraint)
+			m.dropIndices = append(m.dropIndices, sqlStatement{text: fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", name, quoteIdent(con.Name()))})
+		}
+		if ed.to != nil {
+			con := ed.to.(*dbmodel.Constraint)
+			m.createIndices = append(m.createIndices, sqlStatement{text: fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s %s", name, quoteIdent(con.Name()), con.Kind(), con.Content())})
+		}
+	}
+
+	for _, ed := range td.ForeignKeys {
+		if ed.from != nil {
+			fk := ed.from.(*dbmodel.ForeignKey)
+			m.dropForeignKeys = append(m.dropForeignKeys, sqlStatement{text: fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", name, quoteIdent(fk.Name()))})
+		}
+		if ed.to != nil {
+			m.addForeignKeys = append(m.addForeignKeys, sqlStatement{text: sqlAddForeignKey(ed.to.(*dbmodel.ForeignKey))})
+		}
+	}
+}
+
+// addForeignKeysOfDroppedKeys drops foreign keys that reference dropped primary key or unique index,
+// because the key can not be dropped while they depend on it. They are added again when TO schema has them.
+// Foreign keys dropped or added by changes of referencing table are left to the changes.
+func (m *sqlMigration) addForeignKeysOfDroppedKeys(tables []*tableDiff) {
+	dropped := make(map[string]bool)
+	added := make(map[string]bool)
+	for _, td := range tables {
+		for _, ed := range td.ForeignKeys {
+			if ed.from != nil {
+				dropped[foreignKeyKey(ed.from.(*dbmodel.ForeignKey))] = true
+			}
+			if ed.to != nil {
+				added[foreignKeyKey(ed.to.(*dbmodel.ForeignKey))] = true
+			}
+		}
+	}
+	for _, td := range tables {
+		if td.Status != diffChanged {
+			continue
+		}
+		for _, ed := range td.Indices {
+			if ed.from == nil || !ed.from.(*dbmodel.Index).IsUnique() {
+				continue
+			}
+			cols := columnNames(ed.from.(*dbmodel.Index).Columns())
+			for _, fk := range td.from.ReferencedKeys() {
+				key := foreignKeyKey(fk)
+				if dropped[key] || !sameStringSet(referencedColumnNames(fk), cols) {
+					continue
+				}
+				dropped[key] = true
+				m.dropForeignKeys = append(m.dropForeignKeys, sqlStatement{text: fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", qualifiedTableName(fk.Schema(), fk.TableName()), quoteIdent(fk.Name()))})
+				for _, tfk := range td.to.ReferencedKeys() {
+					if foreignKeyKey(tfk) == key && !added[key] {
+						added[key] = true
+						m.addForeignKeys = append(m.addForeignKeys, sqlStatement{text: sqlAddForeignKey(tfk)})
+					}
+				}
+			}
+		}
+	}
+}
+
+// addColumnChanges adds ALTER COLUMN statements. Changes of primary key are migrated by index.
+// Change of type is destructive unless values are converted without loss.
+func (m *sqlMigration) addColumnChanges(tblName string, ed *elementDiff) {
+	col := ed.to.(*dbmodel.Column)
+	alter := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ", tblName, quoteIdent(col.Name()))
+	typeChanged := false
+	for _, c := range ed.Changes {
+		switch c.Attribute {
+		case "type", "size":
+			if !typeChanged {
+				m.alterColumns = append(m.alterColumns, sqlStatement{text: alter + "TYPE " + sqlDataType(col), destructive: !isWideningTypeChange(ed.from.(*dbmodel.Column), col)})
+				typeChanged = true
+			}
+		case "null":
+			if col.IsNullable() {
+				m.alterColumns = append(m.alterColumns, sqlStatement{text: alter + "DROP NOT NULL"})
+			} else {
+				m.alterColumns = append(m.alterColumns, sqlStatement{text: alter + "SET NOT NULL"})
+			}
+		case "default":
+			if col.DefaultValue() == "" {
+				m.alterColumns = append(m.alterColumns, sqlStatement{text: alter + "DROP DEFAULT"})
+			} else {
+				m.alterColumns = append(m.alterColumns, sqlStatement{text: alter + "SET DEFAULT " + col.DefaultValue()})
+			}
+		case "comment":
+			m.alterColumns = append(m.alterColumns, sqlStatement{text: fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", tblName, quoteIdent(col.Name()), sqlCommentText(col.Comment()))})
+		}
+	}
+}
+
+// isWideningTypeChange returns true when type of column is changed to same type with larger size or to wider type.
+func isWideningTypeChange(from *dbmodel.Column, to *dbmodel.Column) bool {
+	if sqlDataType(from) == sqlDataType(to) {
+		return true
+	}
+	fs, ts := from.Size(), to.Size()
+	if from.DataType() == to.DataType() {
+		switch to.DataType() {
+		case "varchar", "bpchar", "varbit":
+			return !ts.Length().Valid || fs.Length().Valid && ts.Length().Int64 >= fs.Length().Int64
+		case "numeric":
+			return !ts.Precision().Valid || fs.Precision().Valid && ts.Scale().Int64 >= fs.Scale().Int64 &&
+				ts.Precision().Int64-ts.Scale().Int64 >= fs.Precision().Int64-fs.Scale().Int64
+		case "timestamp", "timestamptz", "time", "timetz":
+			return timePrecision(ts) >= timePrecision(fs)
+		}
+		return false
+	}
+	if to.DataType() == "numeric" && ts.Precision().Valid {
+		return false
+	}
+	return containsString(sqlWideningTypes[from.DataType()], to.DataType())
+}
+
+// timePrecision returns precision of fractional seconds, which is 6 when it is omitted.
+func timePrecision(size dbmodel.Size) int64 {
+	if size.Precision().Valid {
+		return size.Precision().Int64
+	}
+	return 6
+}
+
+// sortTablesByReference sorts tables so that tables returned by deps come before the table.
+// Only dependencies among given tables are considered, and cycles are ignored.
+func sortTablesByReference(tables []*dbmodel.Table, deps func(*dbmodel.Table) []string) []*dbmodel.Table {
+	tblMap := make(map[string]*dbmodel.Table)
+	for _, tbl := range tables {
+		tblMap[ddlKey(tbl.Schema(), tbl.Name())] = tbl
+	}
+	sorted := make([]*dbmodel.Table, 0, len(tables))
+	visited := make(map[string]bool)
+	var visit func(tbl *dbmodel.Table)
+	visit = func(tbl *dbmodel.Table) {
+		key := ddlKey(tbl.Schema(), tbl.Name())
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, dep := range deps(tbl) {
+			if dt, ok := tblMap[dep]; ok {
+				visit(dt)
+			}
+		}
+		sorted = append(sorted, tbl)
+	}
+	for _, tbl := range tables {
+		visit(tbl)
+	}
+	return sorted
+}
+
+// referencedTables returns keys of tables that are referenced by foreign keys of table.
+func referencedTables(tbl *dbmodel.Table) []string {
+	keys := make([]string, 0, len(tbl.ForeignKeys()))
+	for _, fk := range tbl.ForeignKeys() {
+		if refs := fk.ColumnReferences(); len(refs) > 0 {
+			keys = append(keys, ddlKey(refs[0].To().Schema(), refs[0].To().TableName()))
+		}
+	}
+	return keys
+}
+
+// referencingTables returns keys of tables that reference table by foreign keys.
+func referencingTables(tbl *dbmodel.Table) []string {
+	keys := make([]string, 0, len(tbl.ReferencedKeys()))
+	for _, rk := range tbl.ReferencedKeys() {
+		keys = append(keys, ddlKey(rk.Schema(), rk.TableName()))
+	}
+	return keys
+}
+
+// isPrimaryKeyIndex returns true when index is unique and has just primary key columns.
+// isUniqueConstraintIndex returns true when index is made by UNIQUE constraint, which cannot be dropped by DROP INDEX.
+// Model has no owner of index, so index that has default name of UNIQUE constraint (e.g. users_email_key) is regarded so.
+func isUniqueConstraintIndex(tbl *dbmodel.Table, idx *dbmodel.Index) bool {
+	if !idx.IsUnique() || isPrimaryKeyIndex(tbl, idx) {
+		return false
+	}
+	return idx.Name() == defaultConstraintName(tbl.Name(), columnNames(idx.Columns()), "key")
+}
+
+func isPrimaryKeyIndex(tbl *dbmodel.Table, idx *dbmodel.Index) bool {
+	if !idx.IsUnique() {
+		return false
+	}
+	positions := make(map[string]int64)
+	pkCount := 0
+	for _, col := range tbl.Columns() {
+		positions[col.Name()] = col.PrimaryKeyPosition()
+		if col.PrimaryKeyPosition() > 0 {
+			pkCount++
+		}
+	}
+	if pkCount == 0 || pkCount != len(idx.Columns()) {
+		return false
+	}
+	for i, col := range idx.Columns() {
+		if positions[col.Name()] != int64(i+1) {
+			return false
+		}
+	}
+	return true
+}
+
+func sqlColumnDefinition(col *dbmodel.Column) string {
+	def := quoteIdent(col.Name()) + " "
+	defVal := col.DefaultValue()
+	switch {
+	case col.DataType() == "int2" && isSerial(col):
+		def += "smallserial"
+		defVal = ""
+	case col.DataType() == "int4" && isSerial(col):
+		def += "serial"
+		defVal = ""
+	case col.DataType() == "int8" && isSerial(col):
+		def += "bigserial"
+		defVal = ""
+	default:
+		def += sqlDataType(col)
+	}
+	if !col.IsNullable() {
+		def += " NOT NULL"
+	}
+	if defVal != "" {
+		def += " DEFAULT " + defVal
+	}
+	return def
+}
+
+// sqlDataType returns data type with size as PostgreSQL accepts.
+func sqlDataType(col *dbmodel.Column) string {
+	dataType := col.DataType()
+	if strings.HasPrefix(dataType, "_") {
+		return sqlTypeName(dataType[1:]) + "[]"
+	}
+	size := col.Size()
+	switch dataType {
+	case "varchar", "bpchar", "bit", "varbit":
+		if size.Length().Valid {
+			if dataType == "bpchar" {
+				dataType = "char"
+			}
+			return fmt.Sprintf("%s(%d)", dataType, size.Length().Int64)
+		}
+	case "numeric":
+		if size.Precision().Valid {
+			return fmt.Sprintf("numeric(%d, %d)", size.Precision().Int64, size.Scale().Int64)
+		}
+	case "timestamp", "timestamptz", "time", "timetz":
+		if size.Precision().Valid && size.Precision().Int64 != 6 {
+			return fmt.Sprintf("%s(%d)", dataType, size.Precision().Int64)
+		}
+	}
+	return sqlTypeName(dataType)
+}
+
+// sqlTypeName quotes each part of schema qualified type like domain, because they are folded to lower case unless quoted.
+func sqlTypeName(dataType string) string {
+	if !strings.Contains(dataType, ".") {
+		return dataType
+	}
+	parts := strings.Split(dataType, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdent(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func sqlIndexColumns(idx *dbmodel.Index) string {
+	cols := make([]string, 0, len(idx.Columns()))
+	for _, col := range idx.Columns() {
+		cols = append(cols, quoteIdent(col.Name()))
+	}
+	return strings.Join(cols, ", ")
+}
+
+func sqlCreateIndex(tbl *dbmodel.Table, idx *dbmodel.Index) string {
+	create := "CREATE INDEX"
+	if idx.IsUnique() {
+		create = "CREATE UNIQUE INDEX"
+	}
+	return fmt.Sprintf("%s %s ON %s (%s)", create, quoteIdent(idx.Name()), qualifiedTableName(tbl.Schema(), tbl.Name()), sqlIndexColumns(idx))
+}
+
+func sqlDropIndex(tbl *dbmodel.Table, idx *dbmodel.Index) string {
+	if isPrimaryKeyIndex(tbl, idx) || isUniqueConstraintIndex(tbl, idx) {
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", qualifiedTableName(tbl.Schema(), tbl.Name()), quoteIdent(idx.Name()))
+	}
+	return "DROP INDEX " + qualifiedTableName(tbl.Schema(), idx.Name())
+}
+
+func sqlAddForeignKey(fk *dbmodel.ForeignKey) string {
+	cols := make([]string, 0, len(fk.ColumnReferences()))
+	fcols := make([]string, 0, len(fk.ColumnReferences()))
+	var ftbl string
+	for _, ref := range fk.ColumnReferences() {
+		cols = append(cols, quoteIdent(ref.From().Name()))
+		fcols = append(fcols, quoteIdent(ref.To().Name()))
+		ftbl = qualifiedTableName(ref.To().Schema(), ref.To().TableName())
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		qualifiedTableName(fk.Schema(), fk.TableName()), quoteIdent(fk.Name()), strings.Join(cols, ", "), ftbl, strings.Join(fcols, ", "))
+}
+
+// foreignKeyKey returns key of foreign key that is unique in schemas.
+func foreignKeyKey(fk *dbmodel.ForeignKey) string {
+	return ddlKey(fk.Schema(), fk.TableName()) + "." + fk.Name()
+}
+
+func referencedColumnNames(fk *dbmodel.ForeignKey) []string {
+	names := make([]string, 0, len(fk.ColumnReferences()))
+	for _, ref := range fk.ColumnReferences() {
+		names = append(names, ref.To().Name())
+	}
+	return names
+}
+
+// sqlCommentText returns string literal of comment. Empty comment is NULL that removes comment.
+func sqlCommentText(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return "'" + strings.Replace(comment, "'", "''", -1) + "'"
+}
+
+func qualifiedTableName(schema string, name string) string {
+	if schema == "" {
+		return quoteIdent(name)
+	}
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+// quoteIdent quotes identifier when it is not lower case or it is reserved word.
+func quoteIdent(ident string) string {
+	if plainIdentPattern.MatchString(ident) && !containsString(postgresReservedWords, ident) {
+		return ident
+	}
+	return `"` + strings.Replace(ident, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdDiffSQL(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	from, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	diffOpt.sql = true
+	buf := &bytes.Buffer{}
+	o.out = buf
+	stat := cmdDiff.Run([]string{from, to})
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	expected := `-- Destructive statements are commented out. Use --allow-destructive to enable them.
+
+CREATE TABLE public.posts (
+    id serial NOT NULL,
+    user_id int4 NOT NULL,
+    title text NOT NULL,
+    CONSTRAINT posts_pkey PRIMARY KEY (id)
+);
+COMMENT ON TABLE public.posts IS 'Posts of users';
+ALTER TABLE public.users ADD COLUMN display_name varchar(50) NOT NULL;
+ALTER TABLE public.users ADD COLUMN email varchar(100);
+CREATE UNIQUE INDEX users_email_idx ON public.users (email);
+-- ALTER TABLE public.users DROP COLUMN name;
+ALTER TABLE public.posts ADD CONSTRAINT posts_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id);
+`
+	if actual := buf.String(); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdDiffSQLAllowDestructive(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	from, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	diffOpt.sql = true
+	diffOpt.allowDestructive = true
+	buf := &bytes.Buffer{}
+	o.out = buf
+	cmdDiff.Run([]string{from, to})
+	actual := buf.String()
+	if strings.Contains(actual, "--") {
+		t.Errorf("expected no comment, actual:\n%v", actual)
+	}
+	if !strings.Contains(actual, "\nALTER TABLE public.users DROP COLUMN name;\n") {
+		t.Errorf("expected DROP COLUMN, actual:\n%v", actual)
+	}
+}
+
+func TestCmdDiffSQLNoDifferences(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	_, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	diffOpt.sql = true
+	buf := &bytes.Buffer{}
+	o.out = buf
+	stat := cmdDiff.Run([]string{to, to})
+	if stat != 0 {
+		t.Errorf("expected: %v, actual: %v", 0, stat)
+	}
+	if expected, actual := "-- No differences.\n", buf.String(); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestDiffToSQLTableOrder(t *testing.T) {
+	from := tablesFromDDL(`
+CREATE TABLE a_details (id int PRIMARY KEY, header_id int REFERENCES b_headers (id));
+CREATE TABLE b_headers (id int PRIMARY KEY, customer_id int REFERENCES c_customers (id));
+CREATE TABLE c_customers (id int PRIMARY KEY);
+`)
+	to := tablesFromDDL(`
+CREATE TABLE x_lines (id int PRIMARY KEY, order_id int REFERENCES y_orders (id));
+CREATE TABLE y_orders (id int PRIMARY KEY, shop_id int REFERENCES z_shops (id));
+CREATE TABLE z_shops (id int PRIMARY KEY);
+`)
+	d := diffSchemas(from, to, findConverter(false, "postgres"))
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(diffToSQL(d, true)), "\n") {
+		if strings.HasPrefix(line, "CREATE TABLE") || strings.HasPrefix(line, "DROP TABLE") || strings.Contains(line, "FOREIGN KEY") {
+			lines = append(lines, line)
+		}
+	}
+	expected := []string{
+		"CREATE TABLE public.z_shops (",
+		"CREATE TABLE public.y_orders (",
+		"CREATE TABLE public.x_lines (",
+		"DROP TABLE public.a_details;",
+		"DROP TABLE public.b_headers;",
+		"DROP TABLE public.c_customers;",
+		"ALTER TABLE public.y_orders ADD CONSTRAINT y_orders_shop_id_fkey FOREIGN KEY (shop_id) REFERENCES public.z_shops (id);",
+		"ALTER TABLE public.x_lines ADD CONSTRAINT x_lines_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.y_orders (id);",
+	}
+	if strings.Join(expected, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestDiffToSQLChangedTable(t *testing.T) {
+	from := tablesFromDDL(`
+CREATE TABLE shops (id int PRIMARY KEY);
+CREATE TABLE items (
+    id int PRIMARY KEY,
+    shop_id int REFERENCES shops (id),
+    name varchar(50) NOT NULL,
+    price numeric(8, 2),
+    status int DEFAULT 0,
+    CONSTRAINT ck_items_price CHECK (price >= 0)
+);
+CREATE INDEX items_name_idx ON items (name);
+COMMENT ON TABLE items IS 'Items';
+`)
+	to := tablesFromDDL(`
+CREATE TABLE shops (id int PRIMARY KEY);
+CREATE TABLE items (
+    id int PRIMARY KEY,
+    shop_id int CONSTRAINT fk_items_shop REFERENCES shops (id),
+    name varchar(100),
+    price numeric(10, 2) NOT NULL,
+    status int,
+    CONSTRAINT ck_items_price CHECK (price > 0)
+);
+CREATE UNIQUE INDEX items_name_idx ON items (name);
+COMMENT ON TABLE items IS 'Item''s master';
+COMMENT ON COLUMN items.status IS 'Status';
+`)
+	d := diffSchemas(from, to, findConverter(false, "postgres"))
+	expected := `ALTER TABLE public.items DROP CONSTRAINT items_shop_id_fkey;
+DROP INDEX public.items_name_idx;
+ALTER TABLE public.items DROP CONSTRAINT ck_items_price;
+COMMENT ON TABLE public.items IS 'Item''s master';
+ALTER TABLE public.items ALTER COLUMN name TYPE varchar(100);
+ALTER TABLE public.items ALTER COLUMN name DROP NOT NULL;
+ALTER TABLE public.items ALTER COLUMN price TYPE numeric(10, 2);
+ALTER TABLE public.items ALTER COLUMN price SET NOT NULL;
+ALTER TABLE public.items ALTER COLUMN status DROP DEFAULT;
+COMMENT ON COLUMN public.items.status IS 'Status';
+CREATE UNIQUE INDEX items_name_idx ON public.items (name);
+ALTER TABLE public.items ADD CONSTRAINT ck_items_price CHECK (price > 0);
+ALTER TABLE public.items ADD CONSTRAINT fk_items_shop FOREIGN KEY (shop_id) REFERENCES public.shops (id);
+`
+	if actual := string(diffToSQL(d, false)); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestDiffToSQLUniqueConstraintAndDomain(t *testing.T) {
+	from := tablesFromDDL(`
+CREATE DOMAIN "Flag" AS boolean;
+CREATE TABLE users (id int PRIMARY KEY, email text UNIQUE, code text, name text);
+CREATE UNIQUE INDEX users_code_idx ON users (code);
+`)
+	to := tablesFromDDL(`
+CREATE DOMAIN "Flag" AS boolean;
+CREATE TABLE users (id int PRIMARY KEY, email text, code text, name text, active "Flag", CONSTRAINT users_name_key UNIQUE (name));
+`)
+	d := diffSchemas(from, to, findConverter(false, "postgres"))
+	expected := `DROP INDEX public.users_code_idx;
+ALTER TABLE public.users DROP CONSTRAINT users_email_key;
+ALTER TABLE public.users ADD COLUMN active public."Flag";
+ALTER TABLE public.users ADD CONSTRAINT users_name_key UNIQUE (name);
+`
+	if actual := string(diffToSQL(d, false)); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestDiffToSQLNarrowingType(t *testing.T) {
+	from := tablesFromDDL(`CREATE TABLE items (code varchar(20), name varchar(50), price numeric(10, 2), stock int4, note text);`)
+	to := tablesFromDDL(`CREATE TABLE items (code varchar(10), name text, price numeric(10, 3), stock int8, note varchar(100));`)
+	d := diffSchemas(from, to, findConverter(false, "postgres"))
+	expected := `-- Destructive statements are commented out. Use --allow-destructive to enable them.
+
+-- ALTER TABLE public.items ALTER COLUMN code TYPE varchar(10);
+ALTER TABLE public.items ALTER COLUMN name TYPE text;
+-- ALTER TABLE public.items ALTER COLUMN price TYPE numeric(10, 3);
+ALTER TABLE public.items ALTER COLUMN stock TYPE int8;
+-- ALTER TABLE public.items ALTER COLUMN note TYPE varchar(100);
+`
+	if actual := string(diffToSQL(d, false)); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestDiffToSQLDroppedReferencedKey(t *testing.T) {
+	from := tablesFromDDL(`
+CREATE TABLE shops (id int PRIMARY KEY, code text UNIQUE);
+CREATE TABLE items (id int PRIMARY KEY, shop_id int REFERENCES shops (id), shop_code text REFERENCES shops (code));
+`)
+	to := tablesFromDDL(`
+CREATE TABLE shops (id int, code text, CONSTRAINT shops_pkey PRIMARY KEY (code, id), CONSTRAINT shops_code_key UNIQUE (code));
+CREATE TABLE items (id int PRIMARY KEY, shop_id int, shop_code text REFERENCES shops (code));
+`)
+	d := diffSchemas(from, to, findConverter(false, "postgres"))
+	expected := `ALTER TABLE public.items DROP CONSTRAINT items_shop_id_fkey;
+ALTER TABLE public.shops DROP CONSTRAINT shops_pkey;
+ALTER TABLE public.shops ALTER COLUMN code SET NOT NULL;
+ALTER TABLE public.shops ADD CONSTRAINT shops_pkey PRIMARY KEY (code, id);
+`
+	if actual := string(diffToSQL(d, false)); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	to = tablesFromDDL(`
+CREATE TABLE shops (id int PRIMARY KEY, code text);
+CREATE UNIQUE INDEX shops_code_idx ON shops (code);
+CREATE TABLE items (id int PRIMARY KEY, shop_id int REFERENCES shops (id), shop_code text REFERENCES shops (code));
+`)
+	d = diffSchemas(from, to, findConverter(false, "postgres"))
+	expected = `ALTER TABLE public.items DROP CONSTRAINT items_shop_code_fkey;
+ALTER TABLE public.shops DROP CONSTRAINT shops_code_key;
+CREATE UNIQUE INDEX shops_code_idx ON public.shops (code);
+ALTER TABLE public.items ADD CONSTRAINT items_shop_code_fkey FOREIGN KEY (shop_code) REFERENCES public.shops (code);
+`
+	if actual := string(diffToSQL(d, false)); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		ident    string
+		expected string
+	}{
+		{"users", "users"},
+		{"user", `"user"`},
+		{"Users", `"Users"`},
+		{"order items", `"order items"`},
+		{`a"b`, `"a""b"`},
+	}
+	for _, test := range tests {
+		if actual := quoteIdent(test.ident); test.expected != actual {
+			t.Errorf("expected: %v, actual: %v", test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+func initDiffOpt() {
+	diffOpt.prettyPrint = false
+	diffOpt.format = "text"
+	diffOpt.noColor = false
+	diffOpt.sql = false
+	diffOpt.allowDestructive = false
+}
+
+// setupDiffSnapshots makes snapshots of flyway migrations at version 1 and latest.
+func setupDiffSnapshots(t *testing.T) (string, string) {
+	dir := filepath.Join("test", "tmp")
+	from := filepath.Join(dir, "from.json")
+	to := filepath.Join(dir, "to.json")
+	for path, until := range map[string]string{from: "1", to: ""} {
+		initDumpOpt()
+		o.out = &bytes.Buffer{}
+		o.err = &bytes.Buffer{}
+		setupTestConfigFile("tablarian-migration")
+		dumpOpt.migrationsDir = "test/migrations/flyway"
+		dumpOpt.until = until
+		dumpOpt.output = path
+		if stat := cmdDump.Run([]string{}); stat != 0 {
+			t.Fatal("Dump subcommand should finish normally.")
+		}
+	}
+	return from, to
+}
+
+func TestCmdDiffText(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	from, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	stat := cmdDiff.Run([]string{from, to})
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	expected := `+ posts
+~ users
+    + column display_name
+    + column email
+    - column name
+    + index users_email_idx
+`
+	if actual := buf.String(); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdDiffNoDifferences(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	_, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	stat := cmdDiff.Run([]string{to, to})
+	if stat != 0 {
+		t.Errorf("expected: %v, actual: %v", 0, stat)
+	}
+	if expected, actual := "No differences.\n", buf.String(); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdDiffWithConfig(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	_, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-sqlite")
+	if err := createSqliteTestDB(); err != nil {
+		t.Fatal(err)
+	}
+	stat := cmdDiff.Run([]string{DefaultConfigFileName, to})
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	if !strings.HasPrefix(buf.String(), "- customers\n") {
+		t.Errorf("Tables only in sqlite should be removed. actual: %v", buf.String())
+	}
+}
+
+func TestDiffToTextWithChangedColumn(t *testing.T) {
+	d := diffSchemas(tablesFromDDL("CREATE TABLE users (id int4, name varchar);"),
+		tablesFromDDL("CREATE TABLE users (id int8, name varchar); COMMENT ON TABLE users IS 'Users';"),
+		findConverter(false, "postgres"))
+	expected := `~ users
+      comment:  -> Users
+    ~ column id
+          type: int4 -> int8
+          size: 32, 0 -> 64, 0
+`
+	if actual := string(diffToText(d, false)); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	colored := string(diffToText(d, true))
+	if !strings.HasPrefix(colored, colorYellow+"~ users"+colorReset) {
+		t.Errorf("Changed table should be yellow. actual: %q", colored)
+	}
+}
+
+func tablesFromDDL(sql string) []*dbmodel.Table {
+	s := newDDLSchema(&Config{Driver: "postgres"})
+	s.applySQL("test.sql", sql)
+	return s.build()
+}
+
+func TestCmdDiffMarkdown(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	from, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	diffOpt.format = "markdown"
+	buf := &bytes.Buffer{}
+	o.out = buf
+	cmdDiff.Run([]string{from, to})
+	expected := "# Schema differences\n\n`" + from + "` -> `" + to + "`\n" + `
+## posts (added)
+
+## users (changed)
+
+| OBJECT |      NAME       | STATUS  | ATTRIBUTE | FROM | TO |
+|--------|-----------------|---------|-----------|------|----|
+| column | display_name    | added   |           |      |    |
+| column | email           | added   |           |      |    |
+| column | name            | removed |           |      |    |
+| index  | users_email_idx | added   |           |      |    |
+`
+	if actual := buf.String(); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdDiffJSON(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	from, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	diffOpt.format = "json"
+	buf := &bytes.Buffer{}
+	o.out = buf
+	cmdDiff.Run([]string{from, to})
+	d := &schemaDiff{}
+	if err := json.Unmarshal(buf.Bytes(), d); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Tables) != 2 {
+		t.Fatalf("expected: %v, actual: %v", 2, len(d.Tables))
+	}
+	if expected, actual := "posts:added,users:changed", d.Tables[0].Name+":"+d.Tables[0].Status+","+d.Tables[1].Name+":"+d.Tables[1].Status; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdDiffInvalidFormat(t *testing.T) {
+	defer os.RemoveAll(filepath.Join("test", "tmp"))
+	from, to := setupDiffSnapshots(t)
+	initDiffOpt()
+	diffOpt.format = "foo"
+	ebuf := &bytes.Buffer{}
+	o.out = &bytes.Buffer{}
+	o.err = ebuf
+	if stat := cmdDiff.Run([]string{from, to}); stat != 2 {
+		t.Errorf("expected: %v, actual: %v", 2, stat)
+	}
+	if expected, actual := "Format 'foo' is invalid format.", strings.TrimSpace(ebuf.String()); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// documentFormatVersion is version of structure of JSON and YAML documents.
+// Increment it when structure is changed incompatibly, and update documentJSONSchema.
+const documentFormatVersion = 1
+
+// documentSchemaFileName is file name of bundled JSON Schema that describes documents.
+const documentSchemaFileName = "tablarian.schema.json"
+
+// documentPublisher writes index and table documents for other tools.
+// Documents have same structure in JSON and YAML, and are described by documentJSONSchema.
+type documentPublisher struct {
+	basePublisher
+	ext     string
+	marshal func(v interface{}) ([]byte, error)
+}
+
+type documentIndex struct {
+	FormatVersion    int                   `json:"format_version"`
+	TablarianVersion string                `json:"tablarian_version"`
+	Driver           string                `json:"driver"`
+	Schema           string                `json:"schema"`
+	Tables           []*documentIndexTable `json:"tables"`
+}
+
+type documentIndexTable struct {
+	Schema  string `json:"schema"`
+	Name    string `json:"name"`
+	Comment string `json:"comment"`
+	File    string `json:"file"`
+}
+
+type documentTable struct {
+	FormatVersion  int                      `json:"format_version"`
+	Schema         string                   `json:"schema"`
+	Name           string                   `json:"name"`
+	Comment        string                   `json:"comment"`
+	Columns        []*documentColumn        `json:"columns"`
+	Indices        []*documentTableIndex    `json:"indices"`
+	Constraints    []*documentConstraint    `json:"constraints"`
+	ForeignKeys    []*documentForeignKey    `json:"foreign_keys"`
+	ReferencedKeys []*documentReferencedKey `json:"referenced_keys"`
+}
+
+// documentColumn has raw data type of database and type that converter prints.
+type documentColumn struct {
+	Name               string `json:"name"`
+	DataType           string `json:"data_type"`
+	Type               string `json:"type"`
+	Size               string `json:"size"`
+	Length             *int64 `json:"length"`
+	Precision          *int64 `json:"precision"`
+	Scale              *int64 `json:"scale"`
+	Nullable           bool   `json:"nullable"`
+	Default            string `json:"default"`
+	PrimaryKeyPosition int64  `json:"primary_key_position"`
+	Comment            string `json:"comment"`
+}
+
+type documentTableIndex struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	Unique     bool     `json:"unique"`
+	PrimaryKey bool     `json:"primary_key"`
+}
+
+type documentConstraint struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Content string `json:"content"`
+}
+
+// documentForeignKey has mapping of columns. Columns[i] references ForeignColumns[i].
+type documentForeignKey struct {
+	Name           string   `json:"name"`
+	Columns        []string `json:"columns"`
+	ForeignSchema  string   `json:"foreign_schema"`
+	ForeignTable   string   `json:"foreign_table"`
+	ForeignColumns []string `json:"foreign_columns"`
+}
+
+// documentReferencedKey has mapping of columns. SourceColumns[i] references Columns[i].
+type documentReferencedKey struct {
+	Name          string   `json:"name"`
+	SourceSchema  string   `json:"source_schema"`
+	SourceTable   string   `json:"source_table"`
+	SourceColumns []string `json:"source_columns"`
+	Columns       []string `json:"columns"`
+}
+
+func newJSONPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *documentPublisher {
+	return &documentPublisher{newBasePublisher(config, converter, locale, out, logger), "json", marshalJSONDocument}
+}
+
+func newYAMLPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *documentPublisher {
+	return &documentPublisher{newBasePublisher(config, converter, locale, out, logger), "yaml", marshalYAMLDocument}
+}
+
+func (p *documentPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	idx := &documentIndex{
+		FormatVersion:    documentFormatVersion,
+		TablarianVersion: Version,
+		Driver:           p.cfg.Driver,
+		Schema:           p.cfg.Schema,
+		Tables:           make([]*documentIndexTable, 0, len(tables)),
+	}
+	for _, tbl := range tables {
+		file := tbl.Name() + "." + p.ext
+		idx.Tables = append(idx.Tables, &documentIndexTable{Schema: tbl.Schema(), Name: tbl.Name(), Comment: tbl.Comment(), File: file})
+		p.writeDocument(file, newDocumentTable(tbl, p.conv))
+	}
+	p.writeDocument("00_index."+p.ext, idx)
+	p.write(documentSchemaFileName, []byte(documentJSONSchema))
+}
+
+func (p *documentPublisher) writeDocument(name string, v interface{}) {
+	b, err := p.marshal(v)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write(name, b)
+}
+
+// newDocumentTable makes document of table. Order of elements is kept as source returns.
+func newDocumentTable(tbl *dbmodel.Table, conv Converter) *documentTable {
+	dt := &documentTable{
+		FormatVersion:  documentFormatVersion,
+		Schema:         tbl.Schema(),
+		Name:           tbl.Name(),
+		Comment:        tbl.Comment(),
+		Columns:        make([]*documentColumn, 0, len(tbl.Columns())),
+		Indices:        make([]*documentTableIndex, 0, len(tbl.Indices())),
+		Constraints:    make([]*documentConstraint, 0, len(tbl.Constraints())),
+		ForeignKeys:    make([]*documentForeignKey, 0, len(tbl.ForeignKeys())),
+		ReferencedKeys: make([]*documentReferencedKey, 0, len(tbl.ReferencedKeys())),
+	}
+	for _, col := range tbl.Columns() {
+		values := conv.ConvertColumn(col)
+		size := col.Size()
+		dt.Columns = append(dt.Columns, &documentColumn{
+			Name:               col.Name(),
+			DataType:           col.DataType(),
+			Type:               values[2],
+			Size:               values[3],
+			Length:             nullInt64Ptr(size.Length()),
+			Precision:          nullInt64Ptr(size.Precision()),
+			Scale:              nullInt64Ptr(size.Scale()),
+			Nullable:           col.IsNullable(),
+			Default:            col.DefaultValue(),
+			PrimaryKeyPosition: col.PrimaryKeyPosition(),
+			Comment:            col.Comment(),
+		})
+	}
+	for _, idx := range tbl.Indices() {
+		dt.Indices = append(dt.Indices, &documentTableIndex{
+			Name:       idx.Name(),
+			Columns:    columnNames(idx.Columns()),
+			Unique:     idx.IsUnique(),
+			PrimaryKey: isPrimaryKeyIndex(tbl, idx),
+		})
+	}
+	for _, con := range tbl.Constraints() {
+		dt.Constraints = append(dt.Constraints, &documentConstraint{Name: con.Name(), Kind: con.Kind(), Content: con.Content()})
+	}
+	for _, fk := range tbl.ForeignKeys() {
+		dfk := &documentForeignKey{
+			Name:           fk.Name(),
+			Columns:        make([]string, 0, len(fk.ColumnReferences())),
+			ForeignColumns: make([]string, 0, len(fk.ColumnReferences())),
+		}
+		for _, ref := range fk.ColumnReferences() {
+			dfk.Columns = append(dfk.Columns, ref.From().Name())
+			dfk.ForeignSchema = ref.To().Schema()
+			dfk.ForeignTable = ref.To().TableName()
+			dfk.ForeignColumns = append(dfk.ForeignColumns, ref.To().Name())
+		}
+		dt.ForeignKeys = append(dt.ForeignKeys, dfk)
+	}
+	for _, rk := range tbl.ReferencedKeys() {
+		drk := &documentReferencedKey{
+			Name:          rk.Name(),
+			SourceSchema:  rk.Schema(),
+			SourceTable:   rk.TableName(),
+			SourceColumns: make([]string, 0, len(rk.ColumnReferences())),
+			Columns:       make([]string, 0, len(rk.ColumnReferences())),
+		}
+		for _, ref := range rk.ColumnReferences() {
+			drk.SourceColumns = append(drk.SourceColumns, ref.From().Name())
+			drk.Columns = append(drk.Columns, ref.To().Name())
+		}
+		dt.ReferencedKeys = append(dt.ReferencedKeys, drk)
+	}
+	return dt
+}
+
+// marshalJSONDocument returns indented JSON that ends with new line.
+func marshalJSONDocument(v interface{}) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// marshalYAMLDocument returns YAML that has same structure as JSON document.
+func marshalYAMLDocument(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return convertJSONToYAML(b)
+}
+
+// documentJSONSchema describes index document and table document.
+const documentJSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/pinzolo/tablarian/tablarian.schema.json",
+  "title": "tablarian document",
+  "description": "Index document (00_index.json, 00_index.yaml) or table document (<table>.json, <table>.yaml) written by 'tablarian publish'.",
+  "oneOf": [
+    { "$ref": "#/definitions/index" },
+    { "$ref": "#/definitions/table" }
+  ],
+  "definitions": {
+    "index": {
+      "type": "object",
+      "required": ["format_version", "tablarian_version", "driver", "schema", "tables"],
+      "additionalProperties": false,
+      "properties": {
+        "format_version": { "const": 1 },
+        "tablarian_version": { "type": "string" },
+        "driver": { "type": "string", "description": "Driver in config file." },
+        "schema": { "type": "string", "description": "Schema in config file." },
+        "tables": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "required": ["schema", "name", "comment", "file"],
+            "additionalProperties": false,
+            "properties": {
+              "schema": { "type": "string" },
+              "name": { "type": "string" },
+              "comment": { "type": "string" },
+              "file": { "type": "string", "description": "Relative path of table document." }
+            }
+          }
+        }
+      }
+    },
+    "table": {
+      "type": "object",
+      "required": ["format_version", "schema", "name", "comment", "columns", "indices", "constraints", "foreign_keys", "referenced_keys"],
+      "additionalProperties": false,
+      "properties": {
+        "format_version": { "const": 1 },
+        "schema": { "type": "string" },
+        "name": { "type": "string" },
+        "comment": { "type": "string" },
+        "columns": { "type": "array", "items": { "$ref": "#/definitions/column" } },
+        "indices": { "type": "array", "items": { "$ref": "#/definitions/index_definition" } },
+        "constraints": { "type": "array", "items": { "$ref": "#/definitions/constraint" } },
+        "foreign_keys": { "type": "array", "items": { "$ref": "#/definitions/foreign_key" } },
+        "referenced_keys": { "type": "array", "items": { "$ref": "#/definitions/referenced_key" } }
+      }
+    },
+    "column": {
+      "type": "object",
+      "required": ["name", "data_type", "type", "size", "length", "precision", "scale", "nullable", "default", "primary_key_position", "comment"],
+      "additionalProperties": false,
+      "properties": {
+        "name": { "type": "string" },
+        "data_type": { "type": "string", "description": "Data type that database returns." },
+        "type": { "type": "string", "description": "Data type that is printed by show and publish (converted when --pretty)." },
+        "size": { "type": "string", "description": "Size that is printed by show and publish, empty when column has no size." },
+        "length": { "type": ["integer", "null"] },
+        "precision": { "type": ["integer", "null"] },
+        "scale": { "type": ["integer", "null"] },
+        "nullable": { "type": "boolean" },
+        "default": { "type": "string", "description": "Default expression, empty when column has no default." },
+        "primary_key_position": { "type": "integer", "minimum": 0, "description": "Position in primary key starting with 1, 0 when column is not a part of primary key." },
+        "comment": { "type": "string" }
+      }
+    },
+    "index_definition": {
+      "type": "object",
+      "required": ["name", "columns", "unique", "primary_key"],
+      "additionalProperties": false,
+      "properties": {
+        "name": { "type": "string" },
+        "columns": { "type": "array", "items": { "type": "string" } },
+        "unique": { "type": "boolean" },
+        "primary_key": { "type": "boolean", "description": "True when index is made for primary key." }
+      }
+    },
+    "constraint": {
+      "type": "object",
+      "required": ["name", "kind", "content"],
+      "additionalProperties": false,
+      "properties": {
+        "name": { "type": "string" },
+        "kind": { "type": "string" },
+        "content": { "type": "string" }
+      }
+    },
+    "foreign_key": {
+      "type": "object",
+      "required": ["name", "columns", "foreign_schema", "foreign_table", "foreign_columns"],
+      "additionalProperties": false,
+      "description": "Each of columns references foreign column at same position.",
+      "properties": {
+        "name": { "type": "string" },
+        "columns": { "type": "array", "items": { "type": "string" } },
+        "foreign_schema": { "type": "string" },
+        "foreign_table": { "type": "string" },
+        "foreign_columns": { "type": "array", "items": { "type": "string" } }
+      }
+    },
+    "referenced_key": {
+      "type": "object",
+      "required": ["name", "source_schema", "source_table", "source_columns", "columns"],
+      "additionalProperties": false,
+      "description": "Each of source_columns references column at same position.",
+      "properties": {
+        "name": { "type": "string" },
+        "source_schema": { "type": "string" },
+        "source_table": { "type": "string" },
+        "source_columns": { "type": "array", "items": { "type": "string" } },
+        "columns": { "type": "array", "items": { "type": "string" } }
+      }
+    }
+  }
+}
+`
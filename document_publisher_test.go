@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func publishDocumentWithMigrations(format string) (int, string, error) {
+	if err := initPublishMarkdownTest(); err != nil {
+		return 0, "", err
+	}
+	publishOpt.format = format
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	stat := cmdPublish.Run([]string{})
+	path, err := resolvePath("out")
+	return stat, path, err
+}
+
+// validateDocument checks v by subset of JSON Schema that bundled schema uses.
+func validateDocument(root map[string]interface{}, schema map[string]interface{}, v interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		return validateDocument(root, root["definitions"].(map[string]interface{})[name].(map[string]interface{}), v, path)
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, s := range oneOf {
+			if validateDocument(root, s.(map[string]interface{}), v, path) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s matches %d schemas", path, matched)
+		}
+		return nil
+	}
+	if c, ok := schema["const"]; ok && fmt.Sprint(c) != fmt.Sprint(v) {
+		return fmt.Errorf("%s should be %v", path, c)
+	}
+	if t, ok := schema["type"]; ok {
+		types := make([]string, 0)
+		if ts, ok := t.([]interface{}); ok {
+			for _, s := range ts {
+				types = append(types, s.(string))
+			}
+		} else {
+			types = append(types, t.(string))
+		}
+		actual := "null"
+		switch n := v.(type) {
+		case map[string]interface{}:
+			actual = "object"
+		case []interface{}:
+			actual = "array"
+		case string:
+			actual = "string"
+		case bool:
+			actual = "boolean"
+		case float64:
+			actual = "number"
+			if n == float64(int64(n)) {
+				actual = "integer"
+			}
+		}
+		if !containsString(types, actual) {
+			return fmt.Errorf("%s should be %v, but %s", path, types, actual)
+		}
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		props, _ := schema["properties"].(map[string]interface{})
+		if req, ok := schema["required"].([]interface{}); ok {
+			for _, k := range req {
+				if _, ok := obj[k.(string)]; !ok {
+					return fmt.Errorf("%s should have %s", path, k)
+				}
+			}
+		}
+		for k, value := range obj {
+			s, ok := props[k]
+			if !ok {
+				return fmt.Errorf("%s should not have %s", path, k)
+			}
+			if err := validateDocument(root, s.(map[string]interface{}), value, path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	if arr, ok := v.([]interface{}); ok {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range arr {
+				if err := validateDocument(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestNewDocumentTable(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL)
+	var tbl = tables[0]
+	for _, t := range tables {
+		if t.Name() == "order_items" {
+			tbl = t
+		}
+	}
+	b, err := marshalJSONDocument(newDocumentTable(tbl, findConverter(true, "postgres")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc documentTable
+	if err = json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.FormatVersion != documentFormatVersion || doc.Name != "order_items" {
+		t.Errorf("expected: %v, actual: %v", "order_items", doc.Name)
+	}
+	pks := make([]string, 0)
+	for _, col := range doc.Columns {
+		if col.PrimaryKeyPosition > 0 {
+			pks = append(pks, fmt.Sprintf("%s:%d", col.Name, col.PrimaryKeyPosition))
+		}
+	}
+	if expected, actual := "order_id:1, line:2", strings.Join(pks, ", "); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if len(doc.Indices) != 1 || !doc.Indices[0].PrimaryKey || !doc.Indices[0].Unique {
+		t.Errorf("primary key index should be marked, actual: %+v", doc.Indices)
+	}
+	if len(doc.ForeignKeys) != 1 {
+		t.Fatalf("expected: %v, actual: %v", 1, len(doc.ForeignKeys))
+	}
+	fk := doc.ForeignKeys[0]
+	if expected, actual := "order_items_order_id_fkey: order_id -> public.orders.id", fmt.Sprintf("%s: %s -> %s.%s.%s", fk.Name, strings.Join(fk.Columns, ","), fk.ForeignSchema, fk.ForeignTable, strings.Join(fk.ForeignColumns, ",")); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestConvertJSONToYAML(t *testing.T) {
+	b, err := convertJSONToYAML([]byte(`{
+  "name": "users",
+  "comment": "Users: all\nmembers",
+  "empty": "",
+  "flags": [true, false, null],
+  "reserved": ["yes", "No", "null", "1"],
+  "columns": [
+    {"name": "id", "length": null, "precision": 32, "default": "nextval('users_id_seq'::regclass)"},
+    {"name": "email", "type": "character varying", "nested": [["a", "b"], []], "map": {}}
+  ],
+  "none": []
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `name: users
+comment: "Users: all\nmembers"
+empty: ""
+flags:
+  - true
+  - false
+  - null
+reserved:
+  - "yes"
+  - "No"
+  - "null"
+  - "1"
+columns:
+  - name: id
+    length: null
+    precision: 32
+    default: "nextval('users_id_seq'::regclass)"
+  - name: email
+    type: character varying
+    nested:
+      - - a
+        - b
+      - []
+    map: {}
+none: []
+`
+	if actual := string(b); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestConvertJSONToYAMLWithInvalidJSON(t *testing.T) {
+	if _, err := convertJSONToYAML([]byte(`{"name": `)); err == nil {
+		t.Error("Invalid JSON should make error.")
+	}
+}
+
+func TestCmdPublishJSON(t *testing.T) {
+	stat, dir, err := publishDocumentWithMigrations("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	expected := `Created: out/posts.json
+Created: out/users.json
+Created: out/00_index.json
+Created: out/tablarian.schema.json
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	var schema map[string]interface{}
+	if err = json.Unmarshal([]byte(readPublished(t, dir, "tablarian.schema.json")), &schema); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"00_index.json", "posts.json", "users.json"} {
+		var doc interface{}
+		if err = json.Unmarshal([]byte(readPublished(t, dir, name)), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if err = validateDocument(schema, schema, doc, name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	var idx documentIndex
+	if err = json.Unmarshal([]byte(readPublished(t, dir, "00_index.json")), &idx); err != nil {
+		t.Fatal(err)
+	}
+	files := make([]string, 0)
+	for _, tbl := range idx.Tables {
+		files = append(files, tbl.File)
+	}
+	sort.Strings(files)
+	if expected, actual := "posts.json, users.json", strings.Join(files, ", "); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	var posts documentTable
+	if err = json.Unmarshal([]byte(readPublished(t, dir, "posts.json")), &posts); err != nil {
+		t.Fatal(err)
+	}
+	if posts.Comment != "Posts of users" || len(posts.ForeignKeys) != 1 || posts.ForeignKeys[0].ForeignTable != "users" {
+		t.Errorf("posts.json is invalid, actual: %+v", posts)
+	}
+}
+
+func TestCmdPublishYAML(t *testing.T) {
+	stat, dir, err := publishDocumentWithMigrations("yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	users := readPublished(t, dir, "users.yaml")
+	for _, s := range []string{
+		"format_version: 1\nschema: public\nname: users\n",
+		"columns:\n  - name: id\n    data_type: int4\n",
+		"    nullable: false\n    default: \"nextval('users_id_seq'::regclass)\"\n    primary_key_position: 1\n",
+		"  - name: users_email_idx\n    columns:\n      - email\n    unique: true\n    primary_key: false\n",
+		"referenced_keys:\n  - name: posts_user_id_fkey\n    source_schema: public\n    source_table: posts\n    source_columns:\n      - user_id\n    columns:\n      - id\n",
+	} {
+		if !strings.Contains(users, s) {
+			t.Errorf("users.yaml should contain %s, actual:\n%s", s, users)
+		}
+	}
+	if idx := readPublished(t, dir, "00_index.yaml"); !strings.Contains(idx, "  - schema: public\n    name: posts\n    comment: Posts of users\n    file: posts.yaml\n") {
+		t.Errorf("00_index.yaml should have posts.yaml, actual:\n%s", idx)
+	}
+}
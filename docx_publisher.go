@@ -0,0 +1,306 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const docxFileName = "tables.docx"
+
+// docxPublisher writes a Word document that has cover page, table of contents and chapter per table.
+// Document is written with standard library only, so Office is not required.
+type docxPublisher struct {
+	basePublisher
+}
+
+// docxBody is builder of body of document.xml.
+type docxBody struct {
+	buf *bytes.Buffer
+}
+
+func newDocxPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *docxPublisher {
+	return &docxPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *docxPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	body := &docxBody{buf: &bytes.Buffer{}}
+	title := p.loc.t("document", "title")
+	body.paragraph("Title", title)
+	if sub := p.subtitle(); sub != "" {
+		body.paragraph("Subtitle", sub)
+	}
+	body.pageBreak()
+
+	body.paragraph("TOCHeading", p.loc.t("document", "toc"))
+	body.toc(tables)
+	for i, tbl := range tables {
+		p.tableChapter(body, tbl, i)
+	}
+
+	b, err := buildDocx(title, body.buf.String())
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write(docxFileName, b)
+}
+
+// subtitle returns database and schema of config for cover page.
+func (p *docxPublisher) subtitle() string {
+	names := make([]string, 0, 2)
+	for _, s := range []string{p.cfg.Database, p.cfg.Schema} {
+		if s != "" {
+			names = append(names, s)
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// tableChapter writes chapter that has same sections as table markdown.
+func (p *docxPublisher) tableChapter(body *docxBody, table *dbmodel.Table, i int) {
+	body.heading(table.Name(), i)
+	if table.Comment() != "" {
+		body.paragraph("", table.Comment())
+	}
+
+	rows := make([][]string, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		rows = append(rows, p.conv.ConvertColumn(col))
+	}
+	body.section(p.loc.t("column", "title"), translateHeaders(p.loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"), rows)
+
+	if len(table.Indices()) > 0 {
+		rows = make([][]string, 0, len(table.Indices()))
+		for _, idx := range table.Indices() {
+			rows = append(rows, p.conv.ConvertIndex(idx))
+		}
+		body.section(p.loc.t("index", "title"), translateHeaders(p.loc, "index", "name", "columns", "unique"), rows)
+	}
+
+	if len(table.Constraints()) > 0 {
+		rows = make([][]string, 0, len(table.Constraints()))
+		for _, con := range table.Constraints() {
+			rows = append(rows, p.conv.ConvertConstraint(con))
+		}
+		body.section(p.loc.t("constraint", "title"), translateHeaders(p.loc, "constraint", "name", "kind", "content"), rows)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ForeignKeys()))
+		for _, fk := range table.ForeignKeys() {
+			rows = append(rows, p.conv.ConvertForeignKey(fk))
+		}
+		body.section(p.loc.t("foreign_key", "title"), translateHeaders(p.loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"), rows)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ReferencedKeys()))
+		for _, rk := range table.ReferencedKeys() {
+			rows = append(rows, p.conv.ConvertReferencedKey(rk))
+		}
+		body.section(p.loc.t("referenced_key", "title"), translateHeaders(p.loc, "referenced_key", "name", "source_table", "source_columns", "columns"), rows)
+	}
+}
+
+// paragraph writes paragraph of style. Line breaks in text are kept.
+func (b *docxBody) paragraph(style string, text string) {
+	b.buf.WriteString(`<w:p>`)
+	if style != "" {
+		fmt.Fprintf(b.buf, `<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+	}
+	b.buf.WriteString(docxRun(text, false))
+	b.buf.WriteString(`</w:p>`)
+}
+
+func (b *docxBody) pageBreak() {
+	b.buf.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+}
+
+// heading writes chapter heading with bookmark that table of contents links to.
+func (b *docxBody) heading(text string, i int) {
+	b.buf.WriteString(`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr>`)
+	fmt.Fprintf(b.buf, `<w:bookmarkStart w:id="%d" w:name="%s"/>`, i, docxBookmark(i))
+	b.buf.WriteString(docxRun(text, false))
+	fmt.Fprintf(b.buf, `<w:bookmarkEnd w:id="%d"/>`, i)
+	b.buf.WriteString(`</w:p>`)
+}
+
+// toc writes TOC field. Entries are written in advance, so contents are shown before field is updated.
+func (b *docxBody) toc(tables []*dbmodel.Table) {
+	begin := `<w:r><w:fldChar w:fldCharType="begin" w:dirty="true"/></w:r>` +
+		`<w:r><w:instrText xml:space="preserve"> TOC \o "1-1" \h \z \u </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r>`
+	end := `<w:r><w:fldChar w:fldCharType="end"/></w:r>`
+	if len(tables) == 0 {
+		b.buf.WriteString(`<w:p>` + begin + end + `</w:p>`)
+		return
+	}
+	for i, tbl := range tables {
+		b.buf.WriteString(`<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr>`)
+		if i == 0 {
+			b.buf.WriteString(begin)
+		}
+		fmt.Fprintf(b.buf, `<w:hyperlink w:anchor="%s" w:history="1">%s</w:hyperlink>`, docxBookmark(i), docxRun(tbl.Name(), false))
+		if i == len(tables)-1 {
+			b.buf.WriteString(end)
+		}
+		b.buf.WriteString(`</w:p>`)
+	}
+}
+
+// section writes heading and table. Header row is repeated on each page.
+func (b *docxBody) section(title string, headers []string, rows [][]string) {
+	b.paragraph("Heading2", title)
+	b.buf.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TablarianTable"/><w:tblW w:w="5000" w:type="pct"/><w:tblLook w:firstRow="1" w:lastRow="0" w:firstColumn="0" w:lastColumn="0" w:noHBand="1" w:noVBand="1"/></w:tblPr>`)
+	b.buf.WriteString(`<w:tblGrid>`)
+	for range headers {
+		b.buf.WriteString(`<w:gridCol/>`)
+	}
+	b.buf.WriteString(`</w:tblGrid>`)
+	b.row(headers, true)
+	for _, row := range rows {
+		b.row(row, false)
+	}
+	b.buf.WriteString(`</w:tbl>`)
+	// paragraph is required between tables, otherwise Word joins them.
+	b.buf.WriteString(`<w:p/>`)
+}
+
+func (b *docxBody) row(values []string, header bool) {
+	b.buf.WriteString(`<w:tr>`)
+	if header {
+		b.buf.WriteString(`<w:trPr><w:tblHeader/></w:trPr>`)
+	}
+	for _, v := range values {
+		b.buf.WriteString(`<w:tc>`)
+		if header {
+			b.buf.WriteString(`<w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="D9E2F3"/></w:tcPr>`)
+		}
+		b.buf.WriteString(`<w:p>` + docxRun(v, header) + `</w:p></w:tc>`)
+	}
+	b.buf.WriteString(`</w:tr>`)
+}
+
+// docxRun returns run of text. Line break is written as w:br.
+func docxRun(text string, bold bool) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString(`<w:r>`)
+	if bold {
+		buf.WriteString(`<w:rPr><w:b/></w:rPr>`)
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			buf.WriteString(`<w:br/>`)
+		}
+		fmt.Fprintf(buf, `<w:t xml:space="preserve">%s</w:t>`, xmlEscape(line))
+	}
+	buf.WriteString(`</w:r>`)
+	return buf.String()
+}
+
+// docxBookmark returns bookmark name of chapter of i-th table.
+func docxBookmark(i int) string {
+	return fmt.Sprintf("_Toc%d", i+1)
+}
+
+// buildDocx makes docx file from body of document.
+func buildDocx(title string, body string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"docProps/core.xml", docxCore(title)},
+		{"word/document.xml", docxDocument(body)},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+		{"word/settings.xml", docxSettings},
+	}
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(w, f.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func docxDocument(body string) string {
+	return ooxmlHeader + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` +
+		body +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="567" w:footer="567" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`
+}
+
+func docxCore(title string) string {
+	return ooxmlHeader + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:title>` + xmlEscape(title) + `</dc:title><dc:creator>tablarian</dc:creator></cp:coreProperties>`
+}
+
+const (
+	docxContentTypes = ooxmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+		`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+		`<Override PartName="/word/settings.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"/>` +
+		`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+		`</Types>`
+
+	docxRootRels = ooxmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+		`</Relationships>`
+
+	docxDocumentRels = ooxmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings" Target="settings.xml"/>` +
+		`</Relationships>`
+
+	// docxSettings makes Word update TOC field when document is opened.
+	docxSettings = ooxmlHeader + `<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:updateFields w:val="true"/></w:settings>`
+
+	docxStyles = ooxmlHeader + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Meiryo"/><w:sz w:val="20"/></w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="120"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Subtitle"/>` +
+		`<w:pPr><w:spacing w:before="4000" w:after="400"/><w:jc w:val="center"/></w:pPr><w:rPr><w:b/><w:sz w:val="56"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/>` +
+		`<w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:sz w:val="32"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+		`<w:pPr><w:keepNext/><w:pageBreakBefore/><w:spacing w:after="240"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+		`<w:pPr><w:keepNext/><w:spacing w:before="240"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="26"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="TOCHeading"><w:name w:val="TOC Heading"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+		`<w:pPr><w:spacing w:after="240"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="TOC1"><w:name w:val="toc 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/></w:style>` +
+		`<w:style w:type="table" w:styleId="TablarianTable"><w:name w:val="Tablarian Table"/>` +
+		`<w:pPr><w:spacing w:after="0"/></w:pPr>` +
+		`<w:tblPr><w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/><w:left w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/>` +
+		`<w:bottom w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/><w:right w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/>` +
+		`<w:insideH w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/></w:tblBorders>` +
+		`<w:tblCellMar><w:left w:w="80" w:type="dxa"/><w:right w:w="80" w:type="dxa"/></w:tblCellMar></w:tblPr>` +
+		`<w:tblStylePr w:type="firstRow"><w:rPr><w:b/></w:rPr><w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="D9E2F3"/></w:tcPr></w:tblStylePr>` +
+		`</w:style></w:styles>`
+)
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// dotRecordReplacer escapes characters that have meaning in record label of Graphviz.
+var dotRecordReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+
+// dotPublisher writes relationship graph as Graphviz DOT.
+type dotPublisher struct {
+	basePublisher
+}
+
+func newDotPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *dotPublisher {
+	return &dotPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *dotPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	tbls, err := diagramTables(tables, p.cfg.Diagram)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write("er.dot", convertToDot(tbls, p.conv))
+}
+
+// convertToDot makes digraph that has record node per table in cluster per schema.
+// Edges are drawn from columns of foreign key to referenced columns.
+func convertToDot(tables []*dbmodel.Table, conv Converter) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "digraph tablarian {")
+	fmt.Fprintln(buf, "  graph [rankdir=LR];")
+	fmt.Fprintln(buf, "  node [shape=record, fontsize=10];")
+	fmt.Fprintln(buf, "  edge [fontsize=9];")
+
+	schemas := make([]string, 0)
+	schemaTables := make(map[string][]*dbmodel.Table)
+	for _, tbl := range tables {
+		if _, ok := schemaTables[tbl.Schema()]; !ok {
+			schemas = append(schemas, tbl.Schema())
+		}
+		schemaTables[tbl.Schema()] = append(schemaTables[tbl.Schema()], tbl)
+	}
+	for _, schema := range schemas {
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "  subgraph %s {\n", dotID("cluster_"+schema))
+		fmt.Fprintf(buf, "    label=%s;\n", dotID(schema))
+		for _, tbl := range schemaTables[schema] {
+			fmt.Fprintf(buf, "    %s [label=\"%s\"];\n", dotID(ddlKey(tbl.Schema(), tbl.Name())), dotRecordLabel(tbl, conv))
+		}
+		fmt.Fprintln(buf, "  }")
+	}
+
+	rels := diagramRelations(tables)
+	if len(rels) > 0 {
+		fmt.Fprintln(buf)
+	}
+	for _, rel := range rels {
+		refs := rel.fk.ColumnReferences()
+		pairs := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			pairs = append(pairs, ref.From().Name()+" -> "+ref.To().Name())
+		}
+		fmt.Fprintf(buf, "  %s:%s -> %s:%s [label=%s];\n",
+			dotID(ddlKey(rel.child.Schema(), rel.child.Name())), dotID(refs[0].From().Name()),
+			dotID(ddlKey(rel.parent.Schema(), rel.parent.Name())), dotID(refs[0].To().Name()),
+			`"`+dotEscape(rel.fk.Name())+`\n`+dotEscape(strings.Join(pairs, ", "))+`"`)
+	}
+
+	fmt.Fprintln(buf, "}")
+	return buf.Bytes()
+}
+
+// dotRecordLabel returns label that has table name and fields of columns. Port of field is column name.
+func dotRecordLabel(tbl *dbmodel.Table, conv Converter) string {
+	fields := make([]string, 0, len(tbl.Columns()))
+	for _, col := range tbl.Columns() {
+		text := col.Name() + " : " + diagramColumnType(conv, col)
+		if col.PrimaryKeyPosition() > 0 {
+			text += " (PK)"
+		}
+		fields = append(fields, fmt.Sprintf("<%s> %s\\l", dotRecordReplacer.Replace(col.Name()), dotRecordReplacer.Replace(text)))
+	}
+	return fmt.Sprintf("{%s|%s}", dotRecordReplacer.Replace(tbl.Name()), strings.Join(fields, "|"))
+}
+
+// dotID returns quoted ID.
+func dotID(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+func dotEscape(s string) string {
+	return strings.Replace(strings.Replace(s, `\`, `\\`, -1), `"`, `\"`, -1)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvertToDot(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL + `
+CREATE SCHEMA hr;
+CREATE TABLE hr.staff_grades (staff_id int NOT NULL REFERENCES staffs (id), "grade|rank" char(1));
+`)
+	tbls, err := diagramTables(tables, DiagramConfig{Tables: []string{"order_items", "orders", "staff_grades"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := string(convertToDot(tbls, findConverter(true, "postgres")))
+	expected := `digraph tablarian {
+  graph [rankdir=LR];
+  node [shape=record, fontsize=10];
+  edge [fontsize=9];
+
+  subgraph "cluster_hr" {
+    label="hr";
+    "hr.staff_grades" [label="{staff_grades|<staff_id> staff_id : integer\l|<grade\|rank> grade\|rank : bpchar(1)\l}"];
+  }
+
+  subgraph "cluster_public" {
+    label="public";
+    "public.order_items" [label="{order_items|<order_id> order_id : integer (PK)\l|<line> line : integer (PK)\l}"];
+    "public.orders" [label="{orders|<id> id : serial (PK)\l|<shop_id> shop_id : integer\l}"];
+  }
+
+  "public.order_items":"order_id" -> "public.orders":"id" [label="order_items_order_id_fkey\norder_id -> id"];
+}
+`
+	if expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdPublishDot(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "dot"
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	dot := readPublished(t, dir, "er.dot")
+	if s := `  "public.posts":"user_id" -> "public.users":"id" [label="posts_user_id_fkey\nuser_id -> id"];` + "\n}\n"; !strings.HasSuffix(dot, s) {
+		t.Errorf("er.dot should end with %s, actual:\n%s", s, dot)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type dumpOption struct {
+	baseOption
+	output string
+}
+
+var (
+	cmdDump = &Command{
+		Run:       runDump,
+		UsageLine: "dump ",
+		Short:     "Dump definition of tables to snapshot file.",
+		Long: `Dump definition of tables to JSON snapshot file.
+Snapshot file can be used by --snapshot option of show, index and publish instead of database.
+
+Options:
+    -c CONGIG_FILE, --config CONFIG_FILE
+        use config file instead of default config file(.tablarian.config)
+        if CONFIG_FILE starts with '@', it is treated as absolute file path.
+
+    -o OUTPUT_FILE, --output OUTPUT_FILE
+        write snapshot to OUTPUT_FILE instead of console.
+        if OUTPUT_FILE starts with '@', it is treated as absolute file path.
+
+    --ddl DDL_FILE
+        load table definitions from DDL_FILE instead of connecting to database.
+        if DDL_FILE starts with '@', it is treated as absolute file path.
+
+    --migrations MIGRATIONS_DIR
+        load table definitions by applying up-migrations in MIGRATIONS_DIR in order of version.
+        if MIGRATIONS_DIR starts with '@', it is treated as absolute file path.
+
+    --until VERSION
+        apply migrations until VERSION. (use with --migrations)
+	`,
+	}
+	dumpOpt = dumpOption{}
+)
+
+func init() {
+	cmdDump.Flag.StringVar(&dumpOpt.configFile, "config", DefaultConfigFileName, "Config file path")
+	cmdDump.Flag.StringVar(&dumpOpt.configFile, "c", DefaultConfigFileName, "Config file path")
+	cmdDump.Flag.StringVar(&dumpOpt.output, "output", "", "Output file path")
+	cmdDump.Flag.StringVar(&dumpOpt.output, "o", "", "Output file path")
+	cmdDump.Flag.StringVar(&dumpOpt.ddlFile, "ddl", "", "DDL file path")
+	cmdDump.Flag.StringVar(&dumpOpt.migrationsDir, "migrations", "", "Migrations directory path")
+	cmdDump.Flag.StringVar(&dumpOpt.until, "until", "", "Migration version")
+}
+
+// runDump executes dump command and return exit code.
+func runDump(args []string) int {
+	cfg, err := loadConfig(dumpOpt.configFile)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	src, err := findSource(cfg, dumpOpt.baseOption)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	defer src.Close()
+
+	tables, err := src.AllTables(cfg.Schema)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	b, err := newSnapshot(cfg, tables).marshal()
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+
+	if dumpOpt.output == "" {
+		o.out.Write(b)
+		return 0
+	}
+	path, err := resolvePath(dumpOpt.output)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	if err = writeToFile(path, b); err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func initDumpOpt() {
+	dumpOpt.configFile = DefaultConfigFileName
+	dumpOpt.output = ""
+	dumpOpt.ddlFile = ""
+	dumpOpt.migrationsDir = ""
+	dumpOpt.until = ""
+}
+
+func TestCmdDump(t *testing.T) {
+	initDumpOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-ddl")
+	dumpOpt.ddlFile = "test/create_postgres_resources.sql"
+	stat := cmdDump.Run([]string{})
+	if stat != 0 {
+		t.Error("Dump subcommand should finish normally.")
+	}
+
+	snap := &snapshot{}
+	if err := json.Unmarshal(buf.Bytes(), snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.FormatVersion != snapshotFormatVersion {
+		t.Errorf("expected: %v, actual: %v", snapshotFormatVersion, snap.FormatVersion)
+	}
+	if expected, actual := "postgres/sales", snap.Driver+"/"+snap.Schema; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if len(snap.Tables) != 19 {
+		t.Errorf("expected: %v, actual: %v", 19, len(snap.Tables))
+	}
+	if expected, actual := "country_region_currency", snap.Tables[0].Name; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdDumpIsStable(t *testing.T) {
+	dump := func() string {
+		initDumpOpt()
+		buf := &bytes.Buffer{}
+		o.out = buf
+		o.err = &bytes.Buffer{}
+		setupTestConfigFile("tablarian-ddl")
+		dumpOpt.ddlFile = "test/create_postgres_resources.sql"
+		cmdDump.Run([]string{})
+		return buf.String()
+	}
+	if dump() != dump() {
+		t.Error("Dump subcommand should output same content for same schema.")
+	}
+}
+
+func TestCmdDumpToFileAndShowWithSnapshot(t *testing.T) {
+	dir := filepath.Join("test", "tmp")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "snapshot.json")
+
+	initDumpOpt()
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-ddl")
+	dumpOpt.ddlFile = "test/create_postgres_resources.sql"
+	dumpOpt.output = path
+	if stat := cmdDump.Run([]string{}); stat != 0 {
+		t.Fatal("Dump subcommand should finish normally.")
+	}
+
+	show := func(opt func()) string {
+		initShowOpt()
+		buf := &bytes.Buffer{}
+		o.out = buf
+		o.err = &bytes.Buffer{}
+		showOpt.showAll = true
+		opt()
+		if stat := cmdShow.Run([]string{"sales_order_header"}); stat != 0 {
+			t.Error("Show subcommand should finish normally.")
+		}
+		return buf.String()
+	}
+	expected := show(func() { showOpt.ddlFile = "test/create_postgres_resources.sql" })
+	actual := show(func() { showOpt.snapshotFile = path })
+	if expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdDumpSnapshotRoundTrip(t *testing.T) {
+	dir := filepath.Join("test", "tmp")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "snapshot.json")
+
+	initDumpOpt()
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-ddl")
+	dumpOpt.ddlFile = "test/create_postgres_resources.sql"
+	dumpOpt.output = path
+	cmdDump.Run([]string{})
+
+	snap, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newSnapshot(&Config{Driver: snap.Driver, Schema: snap.Schema}, snap.build()).marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig, _ := ioutil.ReadFile(path)
+	if string(orig) != string(b) {
+		t.Error("Snapshot should not be changed by loading and dumping again.")
+	}
+}
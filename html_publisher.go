@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// htmlPublisher writes static site that works without network.
+type htmlPublisher struct {
+	basePublisher
+}
+
+// htmlPage is data of a page. Index page and table pages are rendered by same template.
+// URLs of page are relative to output directory, and Root is prefix to output directory from the page.
+type htmlPage struct {
+	Root         string
+	Title        string
+	Comment      string
+	IndexTitle   string
+	SearchLabel  string
+	Tables       []htmlLink
+	Sections     []htmlSection
+	DiagramTitle string
+	Diagram      string
+}
+
+type htmlLink struct {
+	Name    string
+	URL     string
+	Current bool
+}
+
+type htmlSection struct {
+	Title   string
+	Headers []string
+	Rows    []htmlRow
+}
+
+type htmlRow struct {
+	ID    string
+	Cells []htmlCell
+}
+
+// htmlCell is a cell of table. Text is linked when URL is not empty.
+type htmlCell struct {
+	Text string
+	URL  string
+}
+
+// htmlSearchEntry is an entry of search index. Column is empty for table entry.
+type htmlSearchEntry struct {
+	Table   string `json:"table"`
+	Column  string `json:"column,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	URL     string `json:"url"`
+}
+
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}assets/style.css">
+</head>
+<body>
+<nav class="sidebar">
+<p class="home"><a href="{{.Root}}index.html">{{.IndexTitle}}</a></p>
+<input type="search" id="search" placeholder="{{.SearchLabel}}" data-root="{{.Root}}">
+<ul id="search-results"></ul>
+<ul class="tables">
+{{- range .Tables}}
+<li{{if .Current}} class="current"{{end}}><a href="{{$.Root}}{{.URL}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</nav>
+<main>
+<h1>{{.Title}}</h1>
+{{- if .Comment}}
+<p class="comment">{{.Comment}}</p>
+{{- end}}
+{{- range .Sections}}
+{{- if .Title}}
+<h2>{{.Title}}</h2>
+{{- end}}
+<table>
+<thead>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr{{if .ID}} id="{{.ID}}"{{end}}>{{range .Cells}}<td>{{if .URL}}<a href="{{$.Root}}{{.URL}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .Diagram}}
+<h2>{{.DiagramTitle}}</h2>
+<p class="diagram"><a href="{{.Root}}{{.Diagram}}"><img src="{{.Root}}{{.Diagram}}" alt="{{.DiagramTitle}}"></a></p>
+{{- end}}
+</main>
+<script src="{{.Root}}search_index.js"></script>
+<script src="{{.Root}}assets/search.js"></script>
+</body>
+</html>
+`))
+
+const htmlStyle = `body {
+  margin: 0;
+  font-family: sans-serif;
+  font-size: 14px;
+  color: #333;
+}
+.sidebar {
+  position: fixed;
+  top: 0;
+  bottom: 0;
+  left: 0;
+  width: 240px;
+  padding: 16px;
+  overflow-y: auto;
+  box-sizing: border-box;
+  background: #f5f5f5;
+  border-right: 1px solid #ddd;
+}
+.sidebar ul {
+  margin: 8px 0;
+  padding: 0;
+  list-style: none;
+}
+.sidebar li {
+  padding: 2px 0;
+}
+.sidebar li.current a {
+  font-weight: bold;
+}
+#search {
+  width: 100%;
+  box-sizing: border-box;
+}
+#search-results:empty {
+  display: none;
+}
+#search-results {
+  padding-bottom: 8px;
+  border-bottom: 1px solid #ddd;
+}
+main {
+  margin-left: 240px;
+  padding: 16px 32px;
+}
+table {
+  border-collapse: collapse;
+  margin-bottom: 16px;
+}
+th, td {
+  padding: 4px 8px;
+  border: 1px solid #ccc;
+  text-align: left;
+}
+th {
+  background: #eee;
+}
+tr:target {
+  background: #ffc;
+}
+a {
+  color: #0366d6;
+}
+`
+
+const htmlSearchScript = `(function () {
+  var input = document.getElementById('search');
+  var results = document.getElementById('search-results');
+  if (!input || !results || !window.searchIndex) {
+    return;
+  }
+  var contains = function (text, query) {
+    return !!text && text.toLowerCase().indexOf(query) >= 0;
+  };
+  input.addEventListener('input', function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = '';
+    if (query === '') {
+      return;
+    }
+    window.searchIndex.forEach(function (entry) {
+      var name = entry.column ? entry.table + '.' + entry.column : entry.table;
+      if (!contains(name, query) && !contains(entry.comment, query)) {
+        return;
+      }
+      var a = document.createElement('a');
+      a.href = (input.getAttribute('data-root') || '') + entry.url;
+      a.textContent = name;
+      if (entry.comment) {
+        a.title = entry.comment;
+      }
+      var li = document.createElement('li');
+      li.appendChild(a);
+      results.appendChild(li);
+    });
+  });
+})();
+`
+
+func newHTMLPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *htmlPublisher {
+	return &htmlPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *htmlPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	svgTbls, err := p.writeSVGDiagrams(tables, func(t *dbmodel.Table) string { return htmlTableURL(t.Name()) })
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	pages := make(map[string]string)
+	for _, tbl := range tables {
+		pages[ddlKey(tbl.Schema(), tbl.Name())] = htmlTableURL(tbl.Name())
+	}
+	for _, tbl := range tables {
+		page := p.tablePage(tbl, tables, pages)
+		page.Root = "../"
+		if containsTable(svgTbls, tbl) {
+			page.Diagram = svgTableDiagramPath(tbl)
+		}
+		p.writePage(htmlTableURL(tbl.Name()), page)
+	}
+	page := p.indexPage(tables)
+	if svgTbls != nil {
+		page.Diagram = "er.svg"
+	}
+	p.writePage("index.html", page)
+
+	entries := htmlSearchEntries(tables)
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write("search_index.json", append(b, '\n'))
+	p.write("search_index.js", []byte("var searchIndex = "+string(b)+";\n"))
+	p.write("assets/style.css", []byte(htmlStyle))
+	p.write("assets/search.js", []byte(htmlSearchScript))
+}
+
+func (p *htmlPublisher) writePage(name string, page *htmlPage) {
+	buf := &bytes.Buffer{}
+	if err := htmlTemplate.Execute(buf, page); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write(name, buf.Bytes())
+}
+
+func (p *htmlPublisher) newPage(title string, tables []*dbmodel.Table, current string) *htmlPage {
+	page := &htmlPage{
+		Title:        title,
+		IndexTitle:   p.loc.t("table_list", "title"),
+		DiagramTitle: p.loc.t("diagram", "title"),
+		SearchLabel:  p.loc.t("table_list", "search"),
+		Tables:       make([]htmlLink, 0, len(tables)),
+	}
+	for _, tbl := range tables {
+		page.Tables = append(page.Tables, htmlLink{Name: tbl.Name(), URL: htmlTableURL(tbl.Name()), Current: tbl.Name() == current})
+	}
+	return page
+}
+
+// indexPage makes page that has same content as index markdown.
+func (p *htmlPublisher) indexPage(tables []*dbmodel.Table) *htmlPage {
+	page := p.newPage(p.loc.t("table_list", "title"), tables, "")
+	sec := htmlSection{Headers: translateHeaders(p.loc, "table_list", "table", "comment")}
+	for _, tbl := range tables {
+		sec.Rows = append(sec.Rows, htmlRow{Cells: []htmlCell{{Text: tbl.Name(), URL: htmlTableURL(tbl.Name())}, {Text: tbl.Comment()}}})
+	}
+	page.Sections = []htmlSection{sec}
+	return page
+}
+
+// tablePage makes page that has same sections as table markdown.
+// Tables of foreign keys and referenced keys are linked when their pages exist.
+func (p *htmlPublisher) tablePage(table *dbmodel.Table, tables []*dbmodel.Table, pages map[string]string) *htmlPage {
+	page := p.newPage(table.Name(), tables, table.Name())
+	page.Comment = table.Comment()
+
+	sec := htmlSection{
+		Title:   p.loc.t("column", "title"),
+		Headers: translateHeaders(p.loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"),
+	}
+	for _, col := range table.Columns() {
+		sec.Rows = append(sec.Rows, htmlRow{ID: htmlColumnID(col.Name()), Cells: htmlCells(p.conv.ConvertColumn(col))})
+	}
+	page.Sections = append(page.Sections, sec)
+
+	if len(table.Indices()) > 0 {
+		sec = htmlSection{
+			Title:   p.loc.t("index", "title"),
+			Headers: translateHeaders(p.loc, "index", "name", "columns", "unique"),
+		}
+		for _, idx := range table.Indices() {
+			sec.Rows = append(sec.Rows, htmlRow{Cells: htmlCells(p.conv.ConvertIndex(idx))})
+		}
+		page.Sections = append(page.Sections, sec)
+	}
+
+	if len(table.Constraints()) > 0 {
+		sec = htmlSection{
+			Title:   p.loc.t("constraint", "title"),
+			Headers: translateHeaders(p.loc, "constraint", "name", "kind", "content"),
+		}
+		for _, con := range table.Constraints() {
+			sec.Rows = append(sec.Rows, htmlRow{Cells: htmlCells(p.conv.ConvertConstraint(con))})
+		}
+		page.Sections = append(page.Sections, sec)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		sec = htmlSection{
+			Title:   p.loc.t("foreign_key", "title"),
+			Headers: translateHeaders(p.loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"),
+		}
+		for _, fk := range table.ForeignKeys() {
+			cells := htmlCells(p.conv.ConvertForeignKey(fk))
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				cells[2].URL = pages[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]
+			}
+			sec.Rows = append(sec.Rows, htmlRow{Cells: cells})
+		}
+		page.Sections = append(page.Sections, sec)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		sec = htmlSection{
+			Title:   p.loc.t("referenced_key", "title"),
+			Headers: translateHeaders(p.loc, "referenced_key", "name", "source_table", "source_columns", "columns"),
+		}
+		for _, rk := range table.ReferencedKeys() {
+			cells := htmlCells(p.conv.ConvertReferencedKey(rk))
+			cells[1].URL = pages[ddlKey(rk.Schema(), rk.TableName())]
+			sec.Rows = append(sec.Rows, htmlRow{Cells: cells})
+		}
+		page.Sections = append(page.Sections, sec)
+	}
+
+	return page
+}
+
+// htmlSearchEntries makes entries of tables and columns for client side search.
+func htmlSearchEntries(tables []*dbmodel.Table) []htmlSearchEntry {
+	entries := make([]htmlSearchEntry, 0)
+	for _, tbl := range tables {
+		url := htmlTableURL(tbl.Name())
+		entries = append(entries, htmlSearchEntry{Table: tbl.Name(), Comment: tbl.Comment(), URL: url})
+		for _, col := range tbl.Columns() {
+			entries = append(entries, htmlSearchEntry{Table: tbl.Name(), Column: col.Name(), Comment: col.Comment(), URL: url + "#" + htmlColumnID(col.Name())})
+		}
+	}
+	return entries
+}
+
+func htmlCells(values []string) []htmlCell {
+	cells := make([]htmlCell, 0, len(values))
+	for _, v := range values {
+		cells = append(cells, htmlCell{Text: v})
+	}
+	return cells
+}
+
+// htmlTableURL returns path of table page.
+// Table pages are put in tables directory so that table named index does not overwrite index page.
+func htmlTableURL(name string) string {
+	return "tables/" + name + ".html"
+}
+
+func htmlColumnID(name string) string {
+	return "column-" + name
+}
@@ -26,6 +26,24 @@ Options:
         use config file instead of default config file(.tablarian.config)
         if CONFIG_FILE starts with '@', it is treated as absolute file path.
 
+    --ddl DDL_FILE
+        load table definitions from DDL_FILE instead of connecting to database.
+        driver in config file is used as SQL dialect.
+        unsupported statements are skipped with warning.
+        if DDL_FILE starts with '@', it is treated as absolute file path.
+
+    --migrations MIGRATIONS_DIR
+        load table definitions by applying up-migrations in MIGRATIONS_DIR in order of version.
+        Flyway(V1__name.sql), golang-migrate(1_name.up.sql) and goose(1_name.sql) file names are acceptable.
+        if MIGRATIONS_DIR starts with '@', it is treated as absolute file path.
+
+    --until VERSION
+        apply migrations until VERSION. (use with --migrations)
+
+    --snapshot SNAPSHOT_FILE
+        load table definitions from SNAPSHOT_FILE made by dump command instead of connecting to database.
+        if SNAPSHOT_FILE starts with '@', it is treated as absolute file path.
+
     -C, --no-comment
         Not print table comment. (default: false)
 	`,
@@ -36,6 +54,10 @@ Options:
 func init() {
 	cmdIndex.Flag.StringVar(&idxOpt.configFile, "config", DefaultConfigFileName, "Config file path")
 	cmdIndex.Flag.StringVar(&idxOpt.configFile, "c", DefaultConfigFileName, "Config file path")
+	cmdIndex.Flag.StringVar(&idxOpt.ddlFile, "ddl", "", "DDL file path")
+	cmdIndex.Flag.StringVar(&idxOpt.migrationsDir, "migrations", "", "Migrations directory path")
+	cmdIndex.Flag.StringVar(&idxOpt.until, "until", "", "Migration version")
+	cmdIndex.Flag.StringVar(&idxOpt.snapshotFile, "snapshot", "", "Snapshot file path")
 	cmdIndex.Flag.BoolVar(&idxOpt.withoutTableComment, "no-comment", false, "Without table comment")
 	cmdIndex.Flag.BoolVar(&idxOpt.withoutTableComment, "C", false, "Without table comment")
 }
@@ -47,11 +69,14 @@ func runIndex(args []string) int {
 		fmt.Fprintln(o.err, err)
 		return 1
 	}
-	db := dbClientFor(cfg)
-	db.Connect()
-	defer db.Disconnect()
+	src, err := findSource(cfg, idxOpt.baseOption)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	defer src.Close()
 
-	tables, err := db.AllTableNames(cfg.Schema)
+	tables, err := src.AllTableNames(cfg.Schema)
 	if err != nil {
 		fmt.Fprintln(o.err, err)
 		return 1
@@ -12,7 +12,7 @@ var cmdInit = &Command{
 	UsageLine: "init ",
 	Short:     "Create config file template.",
 	Long: `Create config template as '.tablarian.config' to working directory.
-Argument is your database sysytem driver name. (current acceptable 'postgres' only.)
+Argument is your database sysytem driver name. (current acceptable 'postgres', 'mysql', 'sqlite3'.)
 	`,
 }
 
@@ -48,6 +48,12 @@ func findConfigContent(d string) (string, error) {
 	if d == "postgres" {
 		return postgresConfigContent(), nil
 	}
+	if d == "mysql" {
+		return mysqlConfigContent(), nil
+	}
+	if d == "sqlite3" {
+		return sqliteConfigContent(), nil
+	}
 	return "", fmt.Errorf("Driver name '%s' is unknow.", d)
 }
 
@@ -68,6 +74,31 @@ func postgresConfigContent() string {
 }`
 }
 
+func mysqlConfigContent() string {
+	return `{
+  "driver": "mysql",
+  "version": "8.0",
+  "host": "localhost",
+  "port": 3306,
+  "user": "root",
+  "password": "your-password",
+  "database": "mysql",
+  "schema": "mysql",
+  "options": {
+    "charset": "utf8mb4"
+  },
+  "out" : "out"
+}`
+}
+
+func sqliteConfigContent() string {
+	return `{
+  "driver": "sqlite3",
+  "database": "path/to/database.sqlite3",
+  "out" : "out"
+}`
+}
+
 func writeConfigContent(cfg string) error {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -35,6 +35,50 @@ func TestCmdInitWithPostgres(t *testing.T) {
 	}
 }
 
+func TestCmdInitWithMysql(t *testing.T) {
+	deleteTestConfigFile()
+	stat := cmdInit.Run([]string{"mysql"})
+	if stat != 0 {
+		t.Error("Init subcommand should finish normally.")
+	}
+
+	path, err := testConfigFilePath()
+	if err != nil {
+		t.Error(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != mysqlConfigContent() {
+		t.Error("Init subcommand wrote invalid content.")
+	}
+}
+
+func TestCmdInitWithSqlite(t *testing.T) {
+	deleteTestConfigFile()
+	stat := cmdInit.Run([]string{"sqlite3"})
+	if stat != 0 {
+		t.Error("Init subcommand should finish normally.")
+	}
+
+	path, err := testConfigFilePath()
+	if err != nil {
+		t.Error(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != sqliteConfigContent() {
+		t.Error("Init subcommand wrote invalid content.")
+	}
+}
+
 func TestCmdInitWithoutArgument(t *testing.T) {
 	buf := &bytes.Buffer{}
 	o.err = buf
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const latexFileName = "tables.tex"
+
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`^`, `\textasciicircum{}`,
+	`_`, `\_`,
+	`%`, `\%`,
+	`~`, `\textasciitilde{}`,
+	"\n", `\newline{}`,
+)
+
+// latexCellReplacer allows line break after underscore in addition to latexReplacer,
+// because long names such as foreign key name have no space to wrap.
+var latexCellReplacer = strings.NewReplacer(`\_`, `\_\allowbreak{}`)
+
+// latexPublisher writes a book that has chapter per table.
+// Book is compiled by lualatex. Book of 'en' loads luatexja too, so that comments written in Japanese are typeset.
+type latexPublisher struct {
+	basePublisher
+}
+
+func newLaTeXPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *latexPublisher {
+	return &latexPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *latexPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	p.write(latexFileName, convertToLaTeX(tables, p.conv, p.loc))
+}
+
+// convertToLaTeX makes book. Chapter of table has same sections as convertToMarkdown.
+// Tables of foreign keys and referenced keys are linked when they are in the book.
+func convertToLaTeX(tables []*dbmodel.Table, conv Converter, loc locale) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "% compile with lualatex")
+	if loc.name == "ja" {
+		fmt.Fprintln(buf, `\documentclass[a4paper]{ltjsbook}`)
+	} else {
+		fmt.Fprintln(buf, `\documentclass[a4paper]{book}`)
+		fmt.Fprintln(buf, `\usepackage{luatexja}`)
+	}
+	fmt.Fprintln(buf, `\usepackage{longtable}`)
+	fmt.Fprintln(buf, `\usepackage{booktabs}`)
+	fmt.Fprintln(buf, `\usepackage[colorlinks=true,linkcolor=blue]{hyperref}`)
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "\\title{%s}\n", latexEscape(loc.t("document", "title")))
+	fmt.Fprintln(buf, `\date{}`)
+	fmt.Fprintf(buf, "\\renewcommand{\\contentsname}{%s}\n", latexEscape(loc.t("document", "toc")))
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, `\begin{document}`)
+	fmt.Fprintln(buf, `\maketitle`)
+	fmt.Fprintln(buf, `\tableofcontents`)
+
+	labels := make(map[string]string)
+	for _, tbl := range tables {
+		labels[ddlKey(tbl.Schema(), tbl.Name())] = latexLabel(tbl.Name())
+	}
+	for _, tbl := range tables {
+		writeLaTeXChapter(buf, tbl, labels, conv, loc)
+	}
+
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, `\end{document}`)
+	return buf.Bytes()
+}
+
+func writeLaTeXChapter(buf *bytes.Buffer, table *dbmodel.Table, labels map[string]string, conv Converter, loc locale) {
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "\\chapter{%s}\\label{%s}\n", latexEscape(table.Name()), latexLabel(table.Name()))
+	if table.Comment() != "" {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, latexEscape(table.Comment()))
+	}
+
+	rows := make([][]string, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		rows = append(rows, latexEscapeRow(conv.ConvertColumn(col)))
+	}
+	writeLaTeXTable(buf, loc.t("column", "title"), latexColumnSpec(0, 0.14, 0.12, 0, 0, 0.12, 0.2), translateHeaders(loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"), rows)
+
+	if len(table.Indices()) > 0 {
+		rows = make([][]string, 0, len(table.Indices()))
+		for _, idx := range table.Indices() {
+			rows = append(rows, latexEscapeRow(conv.ConvertIndex(idx)))
+		}
+		writeLaTeXTable(buf, loc.t("index", "title"), latexColumnSpec(0.4, 0.4, 0), translateHeaders(loc, "index", "name", "columns", "unique"), rows)
+	}
+
+	if len(table.Constraints()) > 0 {
+		rows = make([][]string, 0, len(table.Constraints()))
+		for _, con := range table.Constraints() {
+			rows = append(rows, latexEscapeRow(conv.ConvertConstraint(con)))
+		}
+		writeLaTeXTable(buf, loc.t("constraint", "title"), latexColumnSpec(0.3, 0, 0.5), translateHeaders(loc, "constraint", "name", "kind", "content"), rows)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ForeignKeys()))
+		for _, fk := range table.ForeignKeys() {
+			values := conv.ConvertForeignKey(fk)
+			row := latexEscapeRow(values)
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				if label, ok := labels[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]; ok {
+					row[2] = latexLink(label, values[2])
+				}
+			}
+			rows = append(rows, row)
+		}
+		writeLaTeXTable(buf, loc.t("foreign_key", "title"), latexColumnSpec(0.3, 0.18, 0.18, 0.18), translateHeaders(loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"), rows)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ReferencedKeys()))
+		for _, rk := range table.ReferencedKeys() {
+			values := conv.ConvertReferencedKey(rk)
+			row := latexEscapeRow(values)
+			if label, ok := labels[ddlKey(rk.Schema(), rk.TableName())]; ok {
+				row[1] = latexLink(label, values[1])
+			}
+			rows = append(rows, row)
+		}
+		writeLaTeXTable(buf, loc.t("referenced_key", "title"), latexColumnSpec(0.3, 0.18, 0.18, 0.18), translateHeaders(loc, "referenced_key", "name", "source_table", "source_columns", "columns"), rows)
+	}
+}
+
+// writeLaTeXTable writes section that has longtable. Header is repeated on each page.
+// Values of rows must be escaped, and values that may have line break must be in p column of spec.
+func writeLaTeXTable(buf *bytes.Buffer, title string, spec string, headers []string, rows [][]string) {
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "\\section*{%s}\n", latexEscape(title))
+	fmt.Fprintf(buf, "\\begin{longtable}{%s}\n", spec)
+	fmt.Fprintln(buf, `\toprule`)
+	fmt.Fprintln(buf, strings.Join(latexEscapeRow(headers), " & ")+` \\`)
+	fmt.Fprintln(buf, `\midrule`)
+	fmt.Fprintln(buf, `\endhead`)
+	for _, row := range rows {
+		fmt.Fprintln(buf, strings.Join(row, " & ")+` \\`)
+	}
+	fmt.Fprintln(buf, `\bottomrule`)
+	fmt.Fprintln(buf, `\end{longtable}`)
+}
+
+// latexColumnSpec returns column spec of longtable. Column whose width is 0 is l column,
+// and other column is p column that wraps long text such as name, type, default value and comment.
+func latexColumnSpec(widths ...float64) string {
+	spec := ""
+	for _, w := range widths {
+		if w == 0 {
+			spec += "l"
+		} else {
+			spec += fmt.Sprintf("p{%g\\textwidth}", w)
+		}
+	}
+	return spec
+}
+
+func latexLink(label string, text string) string {
+	return fmt.Sprintf("\\hyperref[%s]{%s}", label, latexCellEscape(text))
+}
+
+// latexLabel returns label of chapter of table.
+func latexLabel(name string) string {
+	return "table:" + diagramAlias(name)
+}
+
+func latexEscapeRow(values []string) []string {
+	escaped := make([]string, 0, len(values))
+	for _, v := range values {
+		escaped = append(escaped, latexCellEscape(v))
+	}
+	return escaped
+}
+
+// latexCellEscape escapes value of table cell. Line break is allowed after underscore.
+func latexCellEscape(s string) string {
+	return latexCellReplacer.Replace(latexEscape(s))
+}
+
+// latexEscape escapes special characters of LaTeX. Line break is kept by \newline.
+func latexEscape(s string) string {
+	return latexReplacer.Replace(s)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertToLaTeX(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id serial PRIMARY KEY, name varchar(50) NOT NULL DEFAULT '50%_off');
+COMMENT ON TABLE shops IS 'Shops & stores';
+COMMENT ON COLUMN shops.name IS 'Name
+of shop';
+CREATE TABLE staffs (id serial PRIMARY KEY, shop_id int NOT NULL REFERENCES shops (id));
+`)
+	if tables[0].Name() != "shops" {
+		tables[0], tables[1] = tables[1], tables[0]
+	}
+
+	expected := `% compile with lualatex
+\documentclass[a4paper]{book}
+\usepackage{luatexja}
+\usepackage{longtable}
+\usepackage{booktabs}
+\usepackage[colorlinks=true,linkcolor=blue]{hyperref}
+
+\title{Table definitions}
+\date{}
+\renewcommand{\contentsname}{Contents}
+
+\begin{document}
+\maketitle
+\tableofcontents
+
+\chapter{shops}\label{table:shops}
+
+Shops \& stores
+
+\section*{Columns}
+\begin{longtable}{lp{0.14\textwidth}p{0.12\textwidth}llp{0.12\textwidth}p{0.2\textwidth}}
+\toprule
+PK & NAME & TYPE & SIZE & NULL & DEFAULT & COMMENT \\
+\midrule
+\endhead
+1 & id & serial &  & NO &  &  \\
+ & name & varchar & 50 & NO & '50\%\_\allowbreak{}off' & Name\newline{}of shop \\
+\bottomrule
+\end{longtable}
+
+\section*{Indices}
+\begin{longtable}{p{0.4\textwidth}p{0.4\textwidth}l}
+\toprule
+NAME & COLUMNS & UNIQUE \\
+\midrule
+\endhead
+shops\_\allowbreak{}pkey & id & YES \\
+\bottomrule
+\end{longtable}
+
+\section*{Referenced keys}
+\begin{longtable}{p{0.3\textwidth}p{0.18\textwidth}p{0.18\textwidth}p{0.18\textwidth}}
+\toprule
+NAME & SOURCE TABLE & SOURCE COLUMNS & COLUMNS \\
+\midrule
+\endhead
+staffs\_\allowbreak{}shop\_\allowbreak{}id\_\allowbreak{}fkey & \hyperref[table:staffs]{staffs} & shop\_\allowbreak{}id & id \\
+\bottomrule
+\end{longtable}
+
+\chapter{staffs}\label{table:staffs}
+
+\section*{Columns}
+\begin{longtable}{lp{0.14\textwidth}p{0.12\textwidth}llp{0.12\textwidth}p{0.2\textwidth}}
+\toprule
+PK & NAME & TYPE & SIZE & NULL & DEFAULT & COMMENT \\
+\midrule
+\endhead
+1 & id & serial &  & NO &  &  \\
+ & shop\_\allowbreak{}id & integer &  & NO &  &  \\
+\bottomrule
+\end{longtable}
+
+\section*{Indices}
+\begin{longtable}{p{0.4\textwidth}p{0.4\textwidth}l}
+\toprule
+NAME & COLUMNS & UNIQUE \\
+\midrule
+\endhead
+staffs\_\allowbreak{}pkey & id & YES \\
+\bottomrule
+\end{longtable}
+
+\section*{Foreign keys}
+\begin{longtable}{p{0.3\textwidth}p{0.18\textwidth}p{0.18\textwidth}p{0.18\textwidth}}
+\toprule
+NAME & COLUMNS & FOREIGN TABLE & FOREIGN COLUMNS \\
+\midrule
+\endhead
+staffs\_\allowbreak{}shop\_\allowbreak{}id\_\allowbreak{}fkey & shop\_\allowbreak{}id & \hyperref[table:shops]{shops} & id \\
+\bottomrule
+\end{longtable}
+
+\end{document}
+`
+	if actual := string(convertToLaTeX(tables, findConverter(true, "postgres"), l("en"))); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestLaTeXColumnSpec(t *testing.T) {
+	expected := `lp{0.14\textwidth}p{0.2\textwidth}l`
+	if actual := latexColumnSpec(0, 0.14, 0.2, 0); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+// TestLaTeXCompile compiles book by lualatex, and it is skipped when lualatex is not installed.
+func TestLaTeXCompile(t *testing.T) {
+	lualatex, err := exec.LookPath("lualatex")
+	if err != nil {
+		t.Skip("lualatex is not installed.")
+	}
+	tables := tablesFromDDL(`
+CREATE TABLE sales_order_header_sales_reason (
+    sales_order_id int PRIMARY KEY,
+    note varchar(100) DEFAULT 'very_long_default_value_that_has_no_space_in_it_at_all'
+);
+COMMENT ON TABLE sales_order_header_sales_reason IS '受注理由';
+COMMENT ON COLUMN sales_order_header_sales_reason.note IS '備考
+二行目';
+ALTER TABLE sales_order_header_sales_reason ADD CONSTRAINT "fk_sales_order_header_sales_reason_sales_order_header_sales_order_id" FOREIGN KEY (sales_order_id) REFERENCES sales_order_header_sales_reason (sales_order_id);
+`)
+	dir, err := ioutil.TempDir("", "tablarian-latex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, lang := range []string{"en", "ja"} {
+		if err = ioutil.WriteFile(filepath.Join(dir, latexFileName), convertToLaTeX(tables, findConverter(true, "postgres"), l(lang)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(lualatex, "-interaction=nonstopmode", "-halt-on-error", latexFileName)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("%s book should be compiled: %v\n%s", lang, err, out)
+		}
+	}
+}
+
+func TestLaTeXEscape(t *testing.T) {
+	expected := `\textbackslash{}\{\}\$\&\#\textasciicircum{}\_\%\textasciitilde{}`
+	if actual := latexEscape(`\{}$&#^_%~`); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdPublishLaTeX(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "latex"
+	publishOpt.locale = "ja"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	if expected, actual := "Created: out/tables.tex\n", strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	actual := readPublished(t, dir, "tables.tex")
+	for _, s := range []string{
+		"% compile with lualatex\n\\documentclass[a4paper]{ltjsbook}\n\\usepackage{longtable}\n",
+		"\\title{テーブル定義書}\n",
+		"\\renewcommand{\\contentsname}{目次}\n",
+		"\\chapter{posts}\\label{table:posts}\n\nPosts of users\n",
+		"\\section*{参照キー}\n",
+		"posts\\_\\allowbreak{}user\\_\\allowbreak{}id\\_\\allowbreak{}fkey & user\\_\\allowbreak{}id & \\hyperref[table:users]{users} & id \\\\\n",
+		"\\chapter{users}\\label{table:users}\n",
+		"posts\\_\\allowbreak{}user\\_\\allowbreak{}id\\_\\allowbreak{}fkey & \\hyperref[table:posts]{posts} & user\\_\\allowbreak{}id & id \\\\\n",
+	} {
+		if !strings.Contains(actual, s) {
+			t.Errorf("tables.tex should contain %s\nactual:\n%v", s, actual)
+		}
+	}
+}
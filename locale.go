@@ -1,16 +1,26 @@
 package main
 
 type locale struct {
+	name string
 	dict map[string]map[string]string
 }
 
 var (
 	en = locale{
+		name: "en",
 		dict: map[string]map[string]string{
 			"table_list": map[string]string{
 				"title":   "Table index",
 				"table":   "TABLE",
 				"comment": "COMMENT",
+				"search":  "Search",
+			},
+			"diagram": map[string]string{
+				"title": "ER diagram",
+			},
+			"document": map[string]string{
+				"title": "Table definitions",
+				"toc":   "Contents",
 			},
 			"column": map[string]string{
 				"title":         "Columns",
@@ -48,14 +58,43 @@ var (
 				"source_columns": "SOURCE COLUMNS",
 				"columns":        "COLUMNS",
 			},
+			"csv": map[string]string{
+				"schema":           "SCHEMA",
+				"table":            "TABLE",
+				"table_comment":    "TABLE COMMENT",
+				"ordinal":          "ORDINAL",
+				"column":           "COLUMN",
+				"data_type":        "TYPE",
+				"size":             "SIZE",
+				"nullable":         "NULLABLE",
+				"default_value":    "DEFAULT",
+				"primary_key":      "PK",
+				"foreign_key":      "FK",
+				"comment":          "COMMENT",
+				"index":            "INDEX",
+				"columns":          "COLUMNS",
+				"unique":           "UNIQUE",
+				"foreign_key_name": "FOREIGN KEY",
+				"foreign_table":    "FOREIGN TABLE",
+				"foreign_columns":  "FOREIGN COLUMNS",
+			},
 		},
 	}
 	ja = locale{
+		name: "ja",
 		dict: map[string]map[string]string{
 			"table_list": map[string]string{
 				"title":   "テーブル一覧",
 				"table":   "テーブル",
 				"comment": "コメント",
+				"search":  "検索",
+			},
+			"diagram": map[string]string{
+				"title": "ER図",
+			},
+			"document": map[string]string{
+				"title": "テーブル定義書",
+				"toc":   "目次",
 			},
 			"column": map[string]string{
 				"title":         "列一覧",
@@ -93,6 +132,26 @@ var (
 				"source_columns": "参照元列",
 				"columns":        "被参照列",
 			},
+			"csv": map[string]string{
+				"schema":           "スキーマ",
+				"table":            "テーブル",
+				"table_comment":    "テーブルコメント",
+				"ordinal":          "順序",
+				"column":           "列名",
+				"data_type":        "型",
+				"size":             "サイズ",
+				"nullable":         "NULL許可",
+				"default_value":    "初期値",
+				"primary_key":      "PK",
+				"foreign_key":      "参照先",
+				"comment":          "コメント",
+				"index":            "インデックス",
+				"columns":          "列",
+				"unique":           "ユニーク",
+				"foreign_key_name": "参照名",
+				"foreign_table":    "参照テーブル",
+				"foreign_columns":  "参照列",
+			},
 		},
 	}
 )
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"text/template"
 
+	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 // DefaultConfigFileName is default using config file name without option.
@@ -42,8 +44,12 @@ type out struct {
 }
 
 type baseOption struct {
-	configFile  string
-	prettyPrint bool
+	configFile    string
+	prettyPrint   bool
+	ddlFile       string
+	migrationsDir string
+	until         string
+	snapshotFile  string
 }
 
 var o = &out{out: os.Stdout, err: os.Stderr}
@@ -72,6 +78,8 @@ var commands = []*Command{
 	cmdShow,
 	cmdPublish,
 	cmdIndex,
+	cmdDump,
+	cmdDiff,
 	cmdInit,
 }
 
@@ -5,67 +5,169 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/pinzolo/dbmodel"
 )
 
+// singleMarkdownFileName is name of file that has all pages.
+const singleMarkdownFileName = "SCHEMA.md"
+
+var markdownHeadingPattern = regexp.MustCompile(`^#{1,6} (.*)$`)
+
+// MarkdownConfig is setting of markdown format. SingleFile writes all pages into SCHEMA.md.
+type MarkdownConfig struct {
+	SingleFile bool `json:"single_file"`
+}
+
+// markdownDiagrams are kinds of diagram that can be embedded in markdown.
+var markdownDiagrams = []string{"mermaid", "plantuml"}
+
 type markdownPublisher struct {
-	cfg    *Config
-	conv   Converter
-	loc    locale
-	logger io.Writer
-	errors []error
+	basePublisher
 }
 
-func newMarkdownPublisher(config *Config, converter Converter, locale locale, logger io.Writer) markdownPublisher {
-	return markdownPublisher{
-		cfg:    config,
-		conv:   converter,
-		loc:    locale,
-		logger: logger,
-		errors: make([]error, 0, 0),
-	}
+func newMarkdownPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *markdownPublisher {
+	return &markdownPublisher{newBasePublisher(config, converter, locale, out, logger)}
 }
 
-func (p markdownPublisher) Publish(tables []*dbmodel.Table) {
-	path, err := resolvePath(p.cfg.Out)
-	if err != nil {
+func (p *markdownPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
 		p.errors = append(p.errors, err)
 		return
 	}
-	if err := cleanDir(path); err != nil {
+
+	var diagramTbls []*dbmodel.Table
+	if p.cfg.Diagram.Markdown != "" {
+		if !containsString(markdownDiagrams, p.cfg.Diagram.Markdown) {
+			p.errors = append(p.errors, fmt.Errorf("Diagram '%s' is invalid diagram.", p.cfg.Diagram.Markdown))
+			return
+		}
+		tbls, err := diagramTables(tables, p.cfg.Diagram)
+		if err != nil {
+			p.errors = append(p.errors, err)
+			return
+		}
+		diagramTbls = tbls
+	}
+
+	if p.cfg.Markdown.SingleFile {
+		p.publishSingleFile(tables, diagramTbls)
+		return
+	}
+
+	svgTbls, err := p.writeSVGDiagrams(tables, func(t *dbmodel.Table) string { return t.Name() + ".md" })
+	if err != nil {
 		p.errors = append(p.errors, err)
 		return
 	}
 
 	for _, tbl := range tables {
-		md := convertToMarkdown(tbl, p.conv, p.loc)
-		fPath := filepath.Join(path, tbl.Name()+".md")
-		err = writeToFile(fPath, md)
-		if err == nil {
-			p.writeCreatedLog(fPath)
-		} else {
+		p.write(tbl.Name()+".md", p.tablePage(tbl, svgTbls, diagramTbls, nil))
+	}
+	p.write("00_index.md", p.indexPage(tables, svgTbls, diagramTbls, func(schema string, name string) string { return name + ".md" }))
+}
+
+// publishSingleFile writes index and all table pages into SCHEMA.md that has table of contents.
+// Links to pages are replaced with links to headings in the document.
+func (p *markdownPublisher) publishSingleFile(tables []*dbmodel.Table, diagramTbls []*dbmodel.Table) {
+	var svgTbls []*dbmodel.Table
+	if p.cfg.Diagram.SVG {
+		tbls, err := diagramTables(tables, p.cfg.Diagram)
+		if err != nil {
 			p.errors = append(p.errors, err)
+			return
 		}
+		svgTbls = tbls
 	}
 
-	idxMd := convertToIndexMarkdown(tables, p.loc)
-	idxPath := filepath.Join(path, "00_index.md")
-	err = writeToFile(idxPath, idxMd)
-	if err == nil {
-		p.writeCreatedLog(idxPath)
-	} else {
+	// Links do not change headings, so anchors are found in pages without links.
+	// Heading of page is its first heading.
+	anchors := markdownAnchors{}
+	title, toc := p.loc.t("document", "title"), p.loc.t("document", "toc")
+	anchors.add(title)
+	anchors.add(toc)
+	idxAnchor := anchors.add(markdownHeadings(p.indexPage(tables, svgTbls, diagramTbls, nil))[0])
+	tblAnchors := make(map[string]string)
+	for _, tbl := range tables {
+		for i, h := range markdownHeadings(p.tablePage(tbl, svgTbls, diagramTbls, nil)) {
+			if anchor := anchors.add(h); i == 0 {
+				tblAnchors[ddlKey(tbl.Schema(), tbl.Name())] = anchor
+			}
+		}
+	}
+	link := func(schema string, name string) string {
+		if anchor, ok := tblAnchors[ddlKey(schema, name)]; ok {
+			return "#" + anchor
+		}
+		return ""
+	}
+
+	_, err := p.writeSVGDiagrams(tables, func(t *dbmodel.Table) string {
+		if url := link(t.Schema(), t.Name()); url != "" {
+			return singleMarkdownFileName + url
+		}
+		return ""
+	})
+	if err != nil {
 		p.errors = append(p.errors, err)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "#", title)
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "##", toc)
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "- [%s](#%s)\n", p.loc.t("table_list", "title"), idxAnchor)
+	for _, tbl := range tables {
+		fmt.Fprintf(buf, "- [%s](%s)\n", tbl.Name(), link(tbl.Schema(), tbl.Name()))
 	}
+	fmt.Fprintln(buf)
+	buf.Write(p.indexPage(tables, svgTbls, diagramTbls, link))
+	for _, tbl := range tables {
+		fmt.Fprintln(buf)
+		buf.Write(p.tablePage(tbl, svgTbls, diagramTbls, link))
+	}
+	p.write(singleMarkdownFileName, buf.Bytes())
 }
 
-func (p markdownPublisher) Errors() []error {
-	return p.errors
+// tablePage makes page of table that has diagrams when table is in svgTbls or diagramTbls.
+func (p *markdownPublisher) tablePage(tbl *dbmodel.Table, svgTbls []*dbmodel.Table, diagramTbls []*dbmodel.Table, link markdownLink) []byte {
+	md := convertToLinkedMarkdown(tbl, p.conv, p.loc, link)
+	if containsTable(svgTbls, tbl) {
+		md = append(md, convertToImageMarkdown(svgTableDiagramPath(tbl), p.loc)...)
+	}
+	if containsTable(diagramTbls, tbl) {
+		// Table page has diagram of the table and tables that reference or are referenced by it.
+		neighbors, _ := diagramTables(diagramTbls, DiagramConfig{Focus: tbl.Name(), Hops: 1})
+		md = append(md, convertToDiagramMarkdown(p.cfg.Diagram.Markdown, neighbors, p.conv, p.loc)...)
+	}
+	return md
+}
+
+// indexPage makes index page that has diagrams of all tables in svgTbls or diagramTbls.
+func (p *markdownPublisher) indexPage(tables []*dbmodel.Table, svgTbls []*dbmodel.Table, diagramTbls []*dbmodel.Table, link markdownLink) []byte {
+	md := convertToLinkedIndexMarkdown(tables, p.loc, link)
+	if svgTbls != nil {
+		md = append(md, convertToImageMarkdown("er.svg", p.loc)...)
+	}
+	if diagramTbls != nil {
+		md = append(md, convertToDiagramMarkdown(p.cfg.Diagram.Markdown, diagramTbls, p.conv, p.loc)...)
+	}
+	return md
 }
 
 func convertToMarkdown(table *dbmodel.Table, conv Converter, loc locale) []byte {
+	return convertToLinkedMarkdown(table, conv, loc, nil)
+}
+
+// convertToLinkedMarkdown makes page of table that links foreign tables and source tables of referenced keys.
+// Table is not linked when link is nil or link returns empty string.
+func convertToLinkedMarkdown(table *dbmodel.Table, conv Converter, loc locale, link markdownLink) []byte {
 	buf := &bytes.Buffer{}
 
 	fmt.Fprintln(buf, "#", table.Name())
@@ -115,7 +217,11 @@ func convertToMarkdown(table *dbmodel.Table, conv Converter, loc locale) []byte
 		w = newMdTableWriter(buf)
 		w.SetHeader(translateHeaders(loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"))
 		for _, fk := range table.ForeignKeys() {
-			w.Append(conv.ConvertForeignKey(fk))
+			row := conv.ConvertForeignKey(fk)
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				row[2] = link.text(row[2], refs[0].To().Schema(), refs[0].To().TableName())
+			}
+			w.Append(row)
 		}
 		w.Render()
 	}
@@ -127,7 +233,9 @@ func convertToMarkdown(table *dbmodel.Table, conv Converter, loc locale) []byte
 		w = newMdTableWriter(buf)
 		w.SetHeader(translateHeaders(loc, "referenced_key", "name", "source_table", "source_columns", "columns"))
 		for _, rk := range table.ReferencedKeys() {
-			w.Append(conv.ConvertReferencedKey(rk))
+			row := conv.ConvertReferencedKey(rk)
+			row[1] = link.text(row[1], rk.Schema(), rk.TableName())
+			w.Append(row)
 		}
 		w.Render()
 	}
@@ -136,6 +244,10 @@ func convertToMarkdown(table *dbmodel.Table, conv Converter, loc locale) []byte
 }
 
 func convertToIndexMarkdown(tables []*dbmodel.Table, loc locale) []byte {
+	return convertToLinkedIndexMarkdown(tables, loc, func(schema string, name string) string { return name + ".md" })
+}
+
+func convertToLinkedIndexMarkdown(tables []*dbmodel.Table, loc locale, link markdownLink) []byte {
 	buf := &bytes.Buffer{}
 
 	fmt.Fprintln(buf, "#", loc.t("table_list", "title"))
@@ -143,13 +255,101 @@ func convertToIndexMarkdown(tables []*dbmodel.Table, loc locale) []byte {
 	w := newMdTableWriter(buf)
 	w.SetHeader(translateHeaders(loc, "table_list", "table", "comment"))
 	for _, tbl := range tables {
-		w.Append([]string{fmt.Sprintf("[%s](%s.md)", tbl.Name(), tbl.Name()), tbl.Comment()})
+		w.Append([]string{link.text(tbl.Name(), tbl.Schema(), tbl.Name()), tbl.Comment()})
 	}
 	w.Render()
 
 	return buf.Bytes()
 }
 
+// convertToDiagramMarkdown makes ER diagram section as code block of kind.
+func convertToDiagramMarkdown(kind string, tables []*dbmodel.Table, conv Converter, loc locale) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "##", loc.t("diagram", "title"))
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "```"+kind)
+	switch kind {
+	case "plantuml":
+		buf.Write(convertToPlantUML(tables, conv))
+	case "mermaid":
+		buf.Write(convertToMermaid(tables, conv))
+	}
+	fmt.Fprintln(buf, "```")
+	return buf.Bytes()
+}
+
+// convertToImageMarkdown makes ER diagram section that shows image of path.
+func convertToImageMarkdown(path string, loc locale) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "##", loc.t("diagram", "title"))
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "![%s](%s)\n", loc.t("diagram", "title"), path)
+	return buf.Bytes()
+}
+
+// markdownLink returns URL of table, or empty string when table is not linked.
+type markdownLink func(schema string, name string) string
+
+// text returns link of text to table. Text is returned as it is when table is not linked.
+func (link markdownLink) text(text string, schema string, name string) string {
+	if link == nil {
+		return text
+	}
+	if url := link(schema, name); url != "" {
+		return fmt.Sprintf("[%s](%s)", text, url)
+	}
+	return text
+}
+
+// markdownAnchors makes anchors of headings in a document.
+// Anchor of duplicated heading has number suffix in the same way as GitHub.
+type markdownAnchors map[string]int
+
+func (a markdownAnchors) add(heading string) string {
+	anchor := markdownAnchor(heading)
+	n := a[anchor]
+	a[anchor] = n + 1
+	if n > 0 {
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+	return anchor
+}
+
+// markdownHeadings returns texts of ATX headings. Lines in code blocks are skipped.
+func markdownHeadings(md []byte) []string {
+	headings := make([]string, 0)
+	inCode := false
+	for _, line := range strings.Split(string(md), "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+			headings = append(headings, strings.TrimSpace(m[1]))
+		}
+	}
+	return headings
+}
+
+// markdownAnchor returns anchor of heading in the same way as GitHub.
+// Letters are lowered, spaces are replaced with hyphens and punctuations are removed.
+func markdownAnchor(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, text)
+}
+
 func newMdTableWriter(w io.Writer) *tablewriter.Table {
 	tw := tablewriter.NewWriter(w)
 	tw.SetAutoWrapText(false)
@@ -193,9 +393,3 @@ func writeToFile(path string, content []byte) error {
 	_, err = f.Write(content)
 	return err
 }
-
-func (p markdownPublisher) writeCreatedLog(path string) {
-	if p.logger != nil {
-		fmt.Fprintln(p.logger, "Created:", path)
-	}
-}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// mermaidTypeReplacer replaces separators in attribute type, and other characters that Mermaid does not accept are
+// replaced by mermaidTypePattern. Attribute type of Mermaid must match [A-Za-z_][A-Za-z0-9\-_\[\]\(\)]*.
+var (
+	mermaidTypeReplacer = strings.NewReplacer(", ", "-", ",", "-", " ", "_")
+	mermaidTypePattern  = regexp.MustCompile(`[^A-Za-z0-9\-_\[\]()]`)
+)
+
+// convertToMermaid makes erDiagram of Mermaid that GitHub renders in markdown.
+func convertToMermaid(tables []*dbmodel.Table, conv Converter) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "erDiagram")
+	for _, tbl := range tables {
+		fmt.Fprintf(buf, "    %s {\n", diagramAlias(tbl.Name()))
+		for _, col := range tbl.Columns() {
+			fmt.Fprintln(buf, "        "+mermaidAttribute(tbl, col, conv))
+		}
+		fmt.Fprintln(buf, "    }")
+	}
+	for _, rel := range diagramRelations(tables) {
+		parent := "||"
+		if rel.parentOptional {
+			parent = "|o"
+		}
+		child := "o|"
+		if rel.childMany {
+			child = "o{"
+		}
+		fmt.Fprintf(buf, "    %s %s--%s %s : \"%s\"\n", diagramAlias(rel.parent.Name()), parent, child, diagramAlias(rel.child.Name()), rel.fk.Name())
+	}
+	return buf.Bytes()
+}
+
+// mermaidAttribute returns attribute line that has type, name, keys and comment.
+func mermaidAttribute(tbl *dbmodel.Table, col *dbmodel.Column, conv Converter) string {
+	attr := mermaidType(diagramColumnType(conv, col)) + " " + diagramAlias(col.Name())
+	keys := make([]string, 0, 2)
+	if col.PrimaryKeyPosition() > 0 {
+		keys = append(keys, "PK")
+	}
+	if isForeignKeyColumn(tbl, col) {
+		keys = append(keys, "FK")
+	}
+	if len(keys) > 0 {
+		attr += " " + strings.Join(keys, ", ")
+	}
+	if col.Comment() != "" {
+		attr += fmt.Sprintf(" \"%s\"", strings.Replace(col.Comment(), "\"", "'", -1))
+	}
+	return attr
+}
+
+// mermaidType returns attribute type that Mermaid accepts, such as public_Flag for public.Flag.
+func mermaidType(typ string) string {
+	typ = mermaidTypePattern.ReplaceAllString(mermaidTypeReplacer.Replace(typ), "_")
+	if typ == "" || (typ[0] >= '0' && typ[0] <= '9') || strings.ContainsRune("-[]()", rune(typ[0])) {
+		return "_" + typ
+	}
+	return typ
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvertToMermaid(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL + `
+CREATE TABLE prices (id serial PRIMARY KEY, amount numeric(10, 2), rate double precision);
+COMMENT ON COLUMN prices.amount IS 'Amount of "price"';
+`)
+	tbls, err := diagramTables(tables, DiagramConfig{Tables: []string{"prices", "shops", "staffs", "staff_profiles"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := string(convertToMermaid(tbls, findConverter(true, "postgres")))
+	expected := `erDiagram
+    prices {
+        serial id PK
+        numeric(10-2) amount "Amount of 'price'"
+        double_precision rate
+    }
+    shops {
+        serial id PK
+        varchar(50) name
+    }
+    staff_profiles {
+        integer staff_id PK, FK
+        text note
+    }
+    staffs {
+        serial id PK
+        integer shop_id FK
+        text name
+    }
+    staffs ||--o| staff_profiles : "staff_profiles_staff_id_fkey"
+    shops |o--o{ staffs : "staffs_shop_id_fkey"
+`
+	if expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestConvertToMermaidWithDomainAndEnum(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE DOMAIN public."Flag" AS boolean;
+CREATE DOMAIN public."OrderNumber" AS varchar(25);
+CREATE TABLE orders (id serial PRIMARY KEY, active public."Flag", number public."OrderNumber");
+`)
+	actual := string(convertToMermaid(tables, findConverter(true, "postgres")))
+	for _, s := range []string{"        public_Flag active\n", "        public_OrderNumber(25) number\n"} {
+		if !strings.Contains(actual, s) {
+			t.Errorf("diagram should contain %s, actual:\n%s", s, actual)
+		}
+	}
+
+	s := newDDLSchema(&Config{Driver: "mysql", Database: "shop"})
+	s.applySQL("test.sql", "CREATE TABLE users (status enum('a','b') NOT NULL);")
+	tables = s.build()
+	expected := "erDiagram\n    users {\n        enum(_a_-_b_) status\n    }\n"
+	if actual := string(convertToMermaid(tables, findConverter(true, "mysql"))); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestMermaidType(t *testing.T) {
+	for typ, expected := range map[string]string{
+		"numeric(10, 2)":         "numeric(10-2)",
+		"double precision":       "double_precision",
+		"public.Flag":            "public_Flag",
+		"public.OrderNumber(25)": "public_OrderNumber(25)",
+		"integer[]":              "integer[]",
+		`"Flag"`:                 "_Flag_",
+		"2d":                     "_2d",
+	} {
+		if actual := mermaidType(typ); expected != actual {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
+
+func publishMarkdownWithDiagram(t *testing.T, config string, diagram string) string {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	publishOpt.diagram = diagram
+	o.out = &bytes.Buffer{}
+	errBuf := &bytes.Buffer{}
+	o.err = errBuf
+	setupTestConfigFile(config)
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatalf("Publish subcommand should finish normally. %s", errBuf.String())
+	}
+	dir, _ := resolvePath("out")
+	return dir
+}
+
+func TestCmdPublishMarkdownWithMermaid(t *testing.T) {
+	dir := publishMarkdownWithDiagram(t, "tablarian-migration", "mermaid")
+
+	idx := readPublished(t, dir, "00_index.md")
+	expected := "\n## ER diagram\n\n```mermaid\nerDiagram\n    posts {\n"
+	if !strings.Contains(idx, expected) {
+		t.Errorf("00_index.md should contain %s, actual:\n%s", expected, idx)
+	}
+	if s := "    users ||--o{ posts : \"posts_user_id_fkey\"\n```\n"; !strings.HasSuffix(idx, s) {
+		t.Errorf("00_index.md should end with %s, actual:\n%s", s, idx)
+	}
+
+	for _, name := range []string{"posts.md", "users.md"} {
+		md := readPublished(t, dir, name)
+		if !strings.Contains(md, "```mermaid\nerDiagram\n    posts {\n") || !strings.Contains(md, "\n    users {\n") {
+			t.Errorf("%s should have diagram of posts and users, actual:\n%s", name, md)
+		}
+	}
+}
+
+func TestCmdPublishMarkdownWithDiagramFilter(t *testing.T) {
+	dir := publishMarkdownWithDiagram(t, "tablarian-diagram-filter", "")
+
+	idx := readPublished(t, dir, "00_index.md")
+	if !strings.Contains(idx, "```mermaid\nerDiagram\n    users {\n") || strings.Contains(idx, "posts {") {
+		t.Errorf("00_index.md should have diagram of only users, actual:\n%s", idx)
+	}
+	if md := readPublished(t, dir, "posts.md"); strings.Contains(md, "```mermaid") {
+		t.Errorf("posts.md should not have diagram, actual:\n%s", md)
+	}
+	if md := readPublished(t, dir, "users.md"); !strings.Contains(md, "```mermaid\nerDiagram\n    users {\n") {
+		t.Errorf("users.md should have diagram, actual:\n%s", md)
+	}
+}
+
+func TestCmdPublishMarkdownWithoutDiagram(t *testing.T) {
+	dir := publishMarkdownWithDiagram(t, "tablarian-diagram", "none")
+	if idx := readPublished(t, dir, "00_index.md"); strings.Contains(idx, "```") {
+		t.Errorf("00_index.md should not have diagram, actual:\n%s", idx)
+	}
+}
+
+func TestCmdPublishMarkdownWithInvalidDiagram(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	publishOpt.diagram = "visio"
+	o.out = &bytes.Buffer{}
+	errBuf := &bytes.Buffer{}
+	o.err = errBuf
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	if s := "Diagram 'visio' is invalid diagram."; !strings.Contains(errBuf.String(), s) {
+		t.Errorf("expected: %v, actual: %v", s, errBuf.String())
+	}
+}
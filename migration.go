@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// Flyway: V1__create_users.sql, V1_1__add_email.sql, V2.1__add_index.sql
+	flywayMigrationPattern = regexp.MustCompile(`^V(\d+(?:[._]\d+)*)__.*\.sql$`)
+	// golang-migrate: 20240101120000_create_users.up.sql, 1_create_users.up.sql
+	upMigrationPattern = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+	// golang-migrate down migration that is not applied
+	downMigrationPattern = regexp.MustCompile(`^\d+_.*\.down\.sql$`)
+	// goose: 20240101120000_create_users.sql, 00001_create_users.sql
+	gooseMigrationPattern = regexp.MustCompile(`^(\d+)_.*\.sql$`)
+	// Flyway undo and repeatable migrations that are not applied
+	flywayIgnoredPattern = regexp.MustCompile(`^(U\d+(?:[._]\d+)*|R)__.*\.sql$`)
+)
+
+// migration is an up-migration file in migrations directory.
+type migration struct {
+	version string
+	file    string
+	goose   bool
+}
+
+// loadMigrations returns up-migrations in dir ordered by version.
+// Files that do not match naming conventions are reported as warnings.
+func loadMigrations(dir string) ([]*migration, []string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migs := make([]*migration, 0)
+	warnings := make([]string, 0)
+	versions := make(map[string]string)
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".sql") {
+			continue
+		}
+		m := parseMigrationFileName(f.Name())
+		if m == nil {
+			if !downMigrationPattern.MatchString(f.Name()) && !flywayIgnoredPattern.MatchString(f.Name()) {
+				warnings = append(warnings, fmt.Sprintf("%s: file name does not match migration naming conventions. (skipped)", f.Name()))
+			}
+			continue
+		}
+		key := normalizeVersion(m.version)
+		if other, ok := versions[key]; ok {
+			return nil, nil, fmt.Errorf("Migration version '%s' is duplicated in '%s' and '%s'.", m.version, other, f.Name())
+		}
+		versions[key] = f.Name()
+		m.file = filepath.Join(dir, f.Name())
+		migs = append(migs, m)
+	}
+	sort.Slice(migs, func(i, j int) bool {
+		return compareVersion(migs[i].version, migs[j].version) < 0
+	})
+	return migs, warnings, nil
+}
+
+func parseMigrationFileName(name string) *migration {
+	if m := flywayMigrationPattern.FindStringSubmatch(name); m != nil {
+		return &migration{version: m[1]}
+	}
+	if m := upMigrationPattern.FindStringSubmatch(name); m != nil {
+		return &migration{version: m[1]}
+	}
+	if downMigrationPattern.MatchString(name) {
+		return nil
+	}
+	if m := gooseMigrationPattern.FindStringSubmatch(name); m != nil {
+		return &migration{version: m[1], goose: true}
+	}
+	return nil
+}
+
+// upSQL returns SQL that is applied on migrating up.
+// When file has goose annotations, only statements in '-- +goose Up' section are returned.
+func (m *migration) upSQL() (string, error) {
+	content, err := ioutil.ReadFile(m.file)
+	if err != nil {
+		return "", err
+	}
+	text := string(content)
+	if !m.goose || !strings.Contains(text, "+goose Up") {
+		return text, nil
+	}
+
+	// lines out of Up section are blanked to keep line numbers in warnings.
+	buf := make([]string, 0)
+	up := false
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for sc.Scan() {
+		line := sc.Text()
+		if directive := gooseDirective(line); directive != "" {
+			if directive == "Up" {
+				up = true
+			} else if directive == "Down" {
+				up = false
+			}
+			buf = append(buf, "")
+			continue
+		}
+		if up {
+			buf = append(buf, line)
+		} else {
+			buf = append(buf, "")
+		}
+	}
+	return strings.Join(buf, "\n"), sc.Err()
+}
+
+// gooseDirective returns directive name of goose annotation line like '-- +goose Up'.
+func gooseDirective(line string) string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "--") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "--"))
+	if len(fields) < 2 || fields[0] != "+goose" {
+		return ""
+	}
+	return fields[1]
+}
+
+// compareVersion compares versions numerically part by part. ('1.10' > '1.9', '1_1' == '1.1')
+func compareVersion(a string, b string) int {
+	as := strings.Split(normalizeVersion(a), ".")
+	bs := strings.Split(normalizeVersion(b), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// normalizeVersion unifies separator to '.' and removes leading zeros of each part.
+func normalizeVersion(v string) string {
+	parts := strings.Split(strings.Replace(v, "_", ".", -1), ".")
+	for i, p := range parts {
+		p = strings.TrimLeft(p, "0")
+		if p == "" {
+			p = "0"
+		}
+		parts[i] = p
+	}
+	// trailing zero parts are not significant. ('1.0' == '1')
+	for len(parts) > 1 && parts[len(parts)-1] == "0" {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
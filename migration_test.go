@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrationsWithFlyway(t *testing.T) {
+	migs, warnings, err := loadMigrations(filepath.Join("test", "migrations", "flyway"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := make([]string, 0, len(migs))
+	for _, m := range migs {
+		versions = append(versions, m.version)
+	}
+	if expected, actual := "1,1_1,2,10", strings.Join(versions, ","); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	expected := "README.sql: file name does not match migration naming conventions. (skipped)"
+	if actual := strings.Join(warnings, "\n"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestLoadMigrationsWithGolangMigrate(t *testing.T) {
+	migs, warnings, err := loadMigrations(filepath.Join("test", "migrations", "migrate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migs) != 2 {
+		t.Fatalf("expected: %v, actual: %v", 2, len(migs))
+	}
+	if expected, actual := "20240101000000_create_users.up.sql", filepath.Base(migs[0].file); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected: %v, actual: %v", 0, warnings)
+	}
+}
+
+func TestMigrationUpSQLWithGoose(t *testing.T) {
+	m := &migration{version: "00001", file: filepath.Join("test", "migrations", "goose", "00001_create_users.sql"), goose: true}
+	text, err := m.upSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(text, "DROP TABLE") {
+		t.Error("Down section should not be contained.")
+	}
+	if expected, actual := "CREATE TABLE users (", strings.Split(text, "\n")[2]; expected != actual {
+		t.Errorf("Line number should be kept. expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1", "2", -1},
+		{"10", "9", 1},
+		{"1.10", "1.9", 1},
+		{"1_1", "1.1", 0},
+		{"00001", "1", 0},
+		{"1.0", "1", 0},
+		{"1", "1.1", -1},
+		{"20240101000000", "20231231235959", 1},
+	}
+	for _, tt := range tests {
+		if actual := compareVersion(tt.a, tt.b); tt.expected != actual {
+			t.Errorf("compareVersion(%v, %v) expected: %v, actual: %v", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
+func TestCmdShowWithMigrations(t *testing.T) {
+	initShowOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	showOpt.migrationsDir = "test/migrations/flyway"
+	stat := cmdShow.Run([]string{"users"})
+	if stat != 0 {
+		t.Error("Show subcommand should finish normally.")
+	}
+	expected := strings.TrimSpace(`
++----+--------------+---------+-------+------+-----------------------------------+---------+
+| PK |     NAME     |  TYPE   | SIZE  | NULL |              DEFAULT              | COMMENT |
++----+--------------+---------+-------+------+-----------------------------------+---------+
+|  1 | id           | int4    | 32, 0 | NO   | nextval('users_id_seq'::regclass) |         |
+|    | display_name | varchar |    50 | NO   |                                   |         |
+|    | email        | varchar |   100 |      |                                   |         |
++----+--------------+---------+-------+------+-----------------------------------+---------+`)
+	if actual := strings.TrimSpace(buf.String()); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdShowWithMigrationsUntil(t *testing.T) {
+	initShowOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	showOpt.migrationsDir = "test/migrations/flyway"
+	showOpt.until = "1"
+	stat := cmdShow.Run([]string{"users"})
+	if stat != 0 {
+		t.Error("Show subcommand should finish normally.")
+	}
+	expected := strings.TrimSpace(`
++----+------+---------+-------+------+-----------------------------------+---------+
+| PK | NAME |  TYPE   | SIZE  | NULL |              DEFAULT              | COMMENT |
++----+------+---------+-------+------+-----------------------------------+---------+
+|  1 | id   | int4    | 32, 0 | NO   | nextval('users_id_seq'::regclass) |         |
+|    | name | varchar |    50 | NO   |                                   |         |
++----+------+---------+-------+------+-----------------------------------+---------+`)
+	if actual := strings.TrimSpace(buf.String()); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdShowWithMigrationsUntilUnknownVersion(t *testing.T) {
+	initShowOpt()
+	ebuf := &bytes.Buffer{}
+	o.out = &bytes.Buffer{}
+	o.err = ebuf
+	setupTestConfigFile("tablarian-migration")
+	showOpt.migrationsDir = "test/migrations/flyway"
+	showOpt.until = "3"
+	stat := cmdShow.Run([]string{"users"})
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	lines := strings.Split(strings.TrimSpace(ebuf.String()), "\n")
+	if expected, actual := "Migration version '3' is not found.", lines[len(lines)-1]; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdIndexWithMigrations(t *testing.T) {
+	tests := []struct {
+		dir      string
+		until    string
+		expected string
+	}{
+		{"test/migrations/flyway", "", "posts  Posts of users\nusers"},
+		{"test/migrations/flyway", "1.1", "users"},
+		{"test/migrations/migrate", "", ""},
+		{"test/migrations/migrate", "20240101000000", "users"},
+		{"test/migrations/goose", "", "users"},
+	}
+	defer func() {
+		idxOpt.migrationsDir = ""
+		idxOpt.until = ""
+	}()
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		o.out = buf
+		o.err = &bytes.Buffer{}
+		setupTestConfigFile("tablarian-migration")
+		idxOpt.withoutTableComment = false
+		idxOpt.migrationsDir = tt.dir
+		idxOpt.until = tt.until
+		if stat := cmdIndex.Run([]string{}); stat != 0 {
+			t.Errorf("Index subcommand should finish normally. (%v, %v)", tt.dir, tt.until)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSpace(line)
+		}
+		if actual := strings.Join(lines, "\n"); tt.expected != actual {
+			t.Errorf("(%v, %v) expected: %v, actual: %v", tt.dir, tt.until, tt.expected, actual)
+		}
+	}
+}
+
+func TestFindSourceWithUntilOnly(t *testing.T) {
+	_, err := findSource(&Config{Driver: "postgres"}, baseOption{until: "1"})
+	if err == nil {
+		t.Error("--until without --migrations should be error.")
+	}
+}
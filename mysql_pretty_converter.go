@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+type mysqlPrettyConverter struct {
+	defaultConverter
+}
+
+func (mpc mysqlPrettyConverter) ConvertColumn(col *dbmodel.Column) []string {
+	base := mpc.defaultConverter.ConvertColumn(col)
+	dataType, params, attrs := parseMysqlColumnType(base[2])
+	size := base[3]
+	switch dataType {
+	case "tinyint":
+		if params == "1" && attrs == "" {
+			dataType = "boolean"
+		}
+		size = ""
+	case "smallint", "mediumint", "int", "integer", "bigint", "float", "double", "real":
+		size = ""
+	case "tinytext", "text", "mediumtext", "longtext", "tinyblob", "blob", "mediumblob", "longblob":
+		size = ""
+	case "datetime", "timestamp", "time":
+		if size == "0" {
+			size = ""
+		}
+	case "enum", "set":
+		dataType = dataType + "(" + params + ")"
+		size = ""
+	}
+	if attrs != "" {
+		dataType = dataType + " " + attrs
+	}
+
+	return []string{base[0], base[1], dataType, size, base[4], base[5], base[6]}
+}
+
+// parseMysqlColumnType splits column type like 'int(10) unsigned' to type name, parameters and attributes.
+func parseMysqlColumnType(colType string) (string, string, string) {
+	i := strings.Index(colType, "(")
+	j := strings.LastIndex(colType, ")")
+	if i < 0 || j < i {
+		fs := strings.Fields(colType)
+		if len(fs) == 0 {
+			return "", "", ""
+		}
+		return fs[0], "", strings.Join(fs[1:], " ")
+	}
+	return colType[:i], colType[i+1 : j], strings.TrimSpace(colType[j+1:])
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+func TestMysqlTinyint1ToBoolean(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Int64: 3, Valid: true},
+		sql.NullInt64{Int64: 0, Valid: true},
+	)
+	col := dbmodel.NewColumn("foo", "users", "active", "Active flag", "tinyint(1)", size, false, "1", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "boolean"; a != e {
+		t.Errorf("Third value should be boolean. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], ""; a != e {
+		t.Errorf("Fourth value should be empty on data type is boolean. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[5], "1"; a != e {
+		t.Errorf("Sixth value should be default value. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlTinyint(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Int64: 3, Valid: true},
+		sql.NullInt64{Int64: 0, Valid: true},
+	)
+	col := dbmodel.NewColumn("foo", "users", "age", "", "tinyint(4)", size, false, "", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "tinyint"; a != e {
+		t.Errorf("Third value should be tinyint. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], ""; a != e {
+		t.Errorf("Fourth value should be empty on data type is tinyint. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlUnsignedInt(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Int64: 10, Valid: true},
+		sql.NullInt64{Int64: 0, Valid: true},
+	)
+	col := dbmodel.NewColumn("foo", "users", "point", "", "int(10) unsigned", size, false, "0", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "int unsigned"; a != e {
+		t.Errorf("Third value should be int unsigned. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], ""; a != e {
+		t.Errorf("Fourth value should be empty on data type is int. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlAutoIncrement(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Int64: 20, Valid: true},
+		sql.NullInt64{Int64: 0, Valid: true},
+	)
+	col := dbmodel.NewColumn("foo", "users", "id", "Primary key of users", "bigint unsigned auto_increment", size, false, "", 1)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[0], "1"; a != e {
+		t.Errorf("First value should be primary key position. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[2], "bigint unsigned auto_increment"; a != e {
+		t.Errorf("Third value should be bigint unsigned auto_increment. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[5], ""; a != e {
+		t.Errorf("Sixth value should be empty on auto increment column. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlVarchar(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Int64: 255, Valid: true},
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Valid: false},
+	)
+	col := dbmodel.NewColumn("foo", "users", "name", "", "varchar(255)", size, true, "", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "varchar"; a != e {
+		t.Errorf("Third value should be varchar. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], "255"; a != e {
+		t.Errorf("Fourth value should be length. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlDecimal(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Int64: 10, Valid: true},
+		sql.NullInt64{Int64: 2, Valid: true},
+	)
+	col := dbmodel.NewColumn("foo", "items", "price", "", "decimal(10,2)", size, false, "0.00", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "decimal"; a != e {
+		t.Errorf("Third value should be decimal. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], "10, 2"; a != e {
+		t.Errorf("Fourth value should be precision and scale. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlEnum(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Int64: 6, Valid: true},
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Valid: false},
+	)
+	col := dbmodel.NewColumn("foo", "users", "status", "", "enum('active','banned')", size, false, "active", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "enum('active','banned')"; a != e {
+		t.Errorf("Third value should be enum with values. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], ""; a != e {
+		t.Errorf("Fourth value should be empty on data type is enum. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlDatetimeWithoutFraction(t *testing.T) {
+	conv := mysqlPrettyConverter{}
+	size := dbmodel.NewSize(
+		sql.NullInt64{Valid: false},
+		sql.NullInt64{Int64: 0, Valid: true},
+		sql.NullInt64{Valid: false},
+	)
+	col := dbmodel.NewColumn("foo", "users", "created_at", "", "datetime", size, false, "CURRENT_TIMESTAMP", 0)
+	data := conv.ConvertColumn(&col)
+	if a, e := data[2], "datetime"; a != e {
+		t.Errorf("Third value should be datetime. expected: %v, actual: %v", e, a)
+	}
+	if a, e := data[3], ""; a != e {
+		t.Errorf("Fourth value should be empty when fractional seconds precision is 0. expected: %v, actual: %v", e, a)
+	}
+}
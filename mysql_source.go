@@ -0,0 +1,418 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const mysqlDefaultPort = 3306
+
+// mysqlStringDefaultReplacer unescapes string literal in column_default of MariaDB.
+var mysqlStringDefaultReplacer = strings.NewReplacer("''", "'", `\'`, "'", `\\`, `\`, `\n`, "\n", `\t`, "\t")
+
+// mysqlSource loads table definitions from information_schema of MySQL/MariaDB.
+type mysqlSource struct {
+	db       *sql.DB
+	database string
+	version  string
+}
+
+func newMysqlSource(config *Config) (*mysqlSource, error) {
+	db, err := sql.Open("mysql", mysqlDataSourceName(config))
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlSource{
+		db:       db,
+		database: config.Database,
+		version:  config.Version,
+	}, nil
+}
+
+func mysqlDataSourceName(c *Config) string {
+	port := c.Port
+	if port == 0 {
+		port = mysqlDefaultPort
+	}
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", c.User, c.Password, c.Host, port, c.Database)
+	if len(c.Options) == 0 {
+		return dsn
+	}
+
+	keys := make([]string, 0, len(c.Options))
+	for k := range c.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]string, 0, len(keys))
+	for _, k := range keys {
+		params = append(params, k+"="+url.QueryEscape(c.Options[k]))
+	}
+	return dsn + "?" + strings.Join(params, "&")
+}
+
+func (s *mysqlSource) AllTableNames(schema string) ([]*dbmodel.Table, error) {
+	tables, _, err := s.loadTables(s.schemaName(schema), "")
+	return tables, err
+}
+
+func (s *mysqlSource) AllTables(schema string) ([]*dbmodel.Table, error) {
+	return s.load(s.schemaName(schema), "", true)
+}
+
+func (s *mysqlSource) Table(schema string, name string, all bool) (*dbmodel.Table, error) {
+	tables, err := s.load(s.schemaName(schema), name, all)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("Table '%s' is not found.", name)
+	}
+	return tables[0], nil
+}
+
+func (s *mysqlSource) Close() {
+	s.db.Close()
+}
+
+// schemaName returns database name when schema is not given, because schema is synonym for database in MySQL.
+func (s *mysqlSource) schemaName(schema string) string {
+	if schema == "" {
+		return s.database
+	}
+	return schema
+}
+
+// supportsCheckConstraint returns true when information_schema.check_constraints is available.
+// Database is asked when version is not enough to decide it.
+func (s *mysqlSource) supportsCheckConstraint() (bool, error) {
+	if supported, known := checkConstraintSupport(s.version); known {
+		return supported, nil
+	}
+	var n int
+	err := s.db.QueryRow(`
+SELECT COUNT(*)
+FROM information_schema.tables
+WHERE table_schema = 'information_schema'
+  AND table_name = 'CHECK_CONSTRAINTS'`).Scan(&n)
+	return n > 0, err
+}
+
+// checkConstraintSupport returns whether version has information_schema.check_constraints.
+// (MySQL 8.0.16 or later, MariaDB 10.2.22 or later)
+// known is false when version is invalid or has no patch number that is needed to decide it.
+func checkConstraintSupport(version string) (supported bool, known bool) {
+	vs := parseMysqlVersion(version)
+	if len(vs) == 0 {
+		return false, false
+	}
+	if vs[0] >= 10 {
+		return versionAtLeast(vs, []int{10, 2, 22})
+	}
+	return versionAtLeast(vs, []int{8, 0, 16})
+}
+
+// quotesColumnDefault returns true when information_schema.columns.column_default is SQL expression.
+// Server version is asked when version in config is not enough to decide it.
+func (s *mysqlSource) quotesColumnDefault() (bool, error) {
+	if quoted, known := columnDefaultQuoting(s.version); known {
+		return quoted, nil
+	}
+	var version string
+	if err := s.db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return false, err
+	}
+	quoted, _ := columnDefaultQuoting(version)
+	return quoted, nil
+}
+
+// columnDefaultQuoting returns whether column_default of version is SQL expression,
+// that has literal NULL and quoted string. (MariaDB 10.2.7 or later)
+func columnDefaultQuoting(version string) (quoted bool, known bool) {
+	vs := parseMysqlVersion(version)
+	if len(vs) == 0 {
+		return false, false
+	}
+	if vs[0] < 10 {
+		return false, true
+	}
+	return versionAtLeast(vs, []int{10, 2, 7})
+}
+
+// parseMysqlVersion returns numbers of version like 10.6.12-MariaDB.
+func parseMysqlVersion(version string) []int {
+	vs := make([]int, 0, 3)
+	for _, part := range strings.SplitN(version, ".", 3) {
+		n, err := strconv.Atoi(leadingDigits(part))
+		if err != nil {
+			break
+		}
+		vs = append(vs, n)
+	}
+	return vs
+}
+
+// versionAtLeast compares version numbers with min. known is false when vs is too short to decide it.
+func versionAtLeast(vs []int, min []int) (bool, bool) {
+	for i, m := range min {
+		if i == len(vs) {
+			return false, false
+		}
+		if vs[i] != m {
+			return vs[i] > m, true
+		}
+	}
+	return true, true
+}
+
+func leadingDigits(s string) string {
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func (s *mysqlSource) load(schema string, name string, all bool) ([]*dbmodel.Table, error) {
+	tables, tblMap, err := s.loadTables(schema, name)
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := s.quotesColumnDefault()
+	if err != nil {
+		return nil, err
+	}
+	colMap, err := s.loadColumns(schema, name, tblMap, quoted)
+	if err != nil {
+		return nil, err
+	}
+	if !all {
+		return tables, nil
+	}
+
+	if err = s.loadIndices(schema, name, tblMap, colMap); err != nil {
+		return nil, err
+	}
+	supported, err := s.supportsCheckConstraint()
+	if err != nil {
+		return nil, err
+	}
+	if supported {
+		if err = s.loadConstraints(schema, name, tblMap); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.loadForeignKeys(schema, name, tblMap, colMap); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func (s *mysqlSource) loadTables(schema string, name string) ([]*dbmodel.Table, map[string]*dbmodel.Table, error) {
+	rows, err := s.db.Query(`
+SELECT table_name, table_comment
+FROM information_schema.tables
+WHERE table_schema = ?
+  AND table_type = 'BASE TABLE'
+  AND (? = '' OR table_name = ?)
+ORDER BY table_name`, schema, name, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tables := make([]*dbmodel.Table, 0)
+	tblMap := make(map[string]*dbmodel.Table)
+	for rows.Next() {
+		var tName, comment string
+		if err = rows.Scan(&tName, &comment); err != nil {
+			return nil, nil, err
+		}
+		tbl := dbmodel.NewTable(schema, tName, comment)
+		tables = append(tables, &tbl)
+		tblMap[tName] = &tbl
+	}
+	return tables, tblMap, rows.Err()
+}
+
+func (s *mysqlSource) loadColumns(schema string, name string, tblMap map[string]*dbmodel.Table, quoted bool) (map[string]*dbmodel.Column, error) {
+	rows, err := s.db.Query(`
+SELECT c.table_name, c.column_name, c.column_comment, c.column_type,
+       c.character_maximum_length, c.numeric_precision, c.numeric_scale, c.datetime_precision,
+       c.is_nullable, c.column_default, c.extra, COALESCE(k.ordinal_position, 0)
+FROM information_schema.columns c
+LEFT JOIN information_schema.key_column_usage k
+  ON k.table_schema = c.table_schema
+ AND k.table_name = c.table_name
+ AND k.column_name = c.column_name
+ AND k.constraint_name = 'PRIMARY'
+WHERE c.table_schema = ?
+  AND (? = '' OR c.table_name = ?)
+ORDER BY c.table_name, c.ordinal_position`, schema, name, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	colMap := make(map[string]*dbmodel.Column)
+	for rows.Next() {
+		var (
+			tName, cName, comment, colType, nullable, extra string
+			length, precision, scale, dtPrecision           sql.NullInt64
+			defVal                                          sql.NullString
+			pkPos                                           int64
+		)
+		err = rows.Scan(&tName, &cName, &comment, &colType, &length, &precision, &scale, &dtPrecision, &nullable, &defVal, &extra, &pkPos)
+		if err != nil {
+			return nil, err
+		}
+		tbl, ok := tblMap[tName]
+		if !ok {
+			continue
+		}
+		if !precision.Valid {
+			precision = dtPrecision
+		}
+		col := dbmodel.NewColumn(schema, tName, cName, comment, mysqlColumnType(colType, extra),
+			dbmodel.NewSize(length, precision, scale), nullable == "YES", mysqlColumnDefault(defVal, quoted), pkPos)
+		tbl.AddColumn(&col)
+		colMap[columnKey(schema, tName, cName)] = &col
+	}
+	return colMap, rows.Err()
+}
+
+// mysqlColumnType returns column type that has auto_increment attribute in extra, like 'int unsigned auto_increment'.
+// Attribute is kept in type because column has no other place for it, and it is not default value.
+func mysqlColumnType(colType string, extra string) string {
+	if strings.Contains(strings.ToLower(extra), "auto_increment") && !strings.HasSuffix(colType, " auto_increment") {
+		return colType + " auto_increment"
+	}
+	return colType
+}
+
+// mysqlColumnDefault returns default value in same form as MySQL.
+// When quoted, column_default of MariaDB has literal NULL for no default and quoted string literal.
+func mysqlColumnDefault(defVal sql.NullString, quoted bool) string {
+	v := defVal.String
+	if !defVal.Valid || !quoted {
+		return v
+	}
+	if v == "NULL" {
+		return ""
+	}
+	if len(v) >= 2 && strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'") {
+		return mysqlStringDefaultReplacer.Replace(v[1 : len(v)-1])
+	}
+	return v
+}
+
+func (s *mysqlSource) loadIndices(schema string, name string, tblMap map[string]*dbmodel.Table, colMap map[string]*dbmodel.Column) error {
+	rows, err := s.db.Query(`
+SELECT table_name, index_name, non_unique, column_name
+FROM information_schema.statistics
+WHERE table_schema = ?
+  AND (? = '' OR table_name = ?)
+ORDER BY table_name, index_name, seq_in_index`, schema, name, name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var idx *dbmodel.Index
+	for rows.Next() {
+		var (
+			tName, iName string
+			nonUnique    int64
+			cName        sql.NullString
+		)
+		if err = rows.Scan(&tName, &iName, &nonUnique, &cName); err != nil {
+			return err
+		}
+		tbl, ok := tblMap[tName]
+		if !ok {
+			continue
+		}
+		if idx == nil || idx.TableName() != tName || idx.Name() != iName {
+			i := dbmodel.NewIndex(schema, tName, iName, nonUnique == 0)
+			idx = &i
+			tbl.AddIndex(idx)
+		}
+		// functional index part has no column name
+		if col, ok := colMap[columnKey(schema, tName, cName.String)]; ok {
+			idx.AddColumn(col)
+		}
+	}
+	return rows.Err()
+}
+
+func (s *mysqlSource) loadConstraints(schema string, name string, tblMap map[string]*dbmodel.Table) error {
+	rows, err := s.db.Query(`
+SELECT tc.table_name, tc.constraint_name, cc.check_clause
+FROM information_schema.table_constraints tc
+JOIN information_schema.check_constraints cc
+  ON cc.constraint_schema = tc.constraint_schema
+ AND cc.constraint_name = tc.constraint_name
+WHERE tc.table_schema = ?
+  AND tc.constraint_type = 'CHECK'
+  AND (? = '' OR tc.table_name = ?)
+ORDER BY tc.table_name, tc.constraint_name`, schema, name, name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tName, cName, content string
+		if err = rows.Scan(&tName, &cName, &content); err != nil {
+			return err
+		}
+		if tbl, ok := tblMap[tName]; ok {
+			con := dbmodel.NewConstraint(schema, tName, cName, "CHECK", content)
+			tbl.AddConstraint(&con)
+		}
+	}
+	return rows.Err()
+}
+
+func (s *mysqlSource) loadForeignKeys(schema string, name string, tblMap map[string]*dbmodel.Table, colMap map[string]*dbmodel.Column) error {
+	rows, err := s.db.Query(`
+SELECT constraint_name, table_schema, table_name, column_name,
+       referenced_table_schema, referenced_table_name, referenced_column_name
+FROM information_schema.key_column_usage
+WHERE referenced_table_name IS NOT NULL
+  AND ((table_schema = ? AND (? = '' OR table_name = ?))
+    OR (referenced_table_schema = ? AND (? = '' OR referenced_table_name = ?)))
+ORDER BY constraint_name, table_schema, table_name, ordinal_position`, schema, name, name, schema, name, name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var fk *dbmodel.ForeignKey
+	for rows.Next() {
+		var fkName, fSchema, fTable, fCol, tSchema, tTable, tCol string
+		if err = rows.Scan(&fkName, &fSchema, &fTable, &fCol, &tSchema, &tTable, &tCol); err != nil {
+			return err
+		}
+		if fk == nil || fk.Schema() != fSchema || fk.TableName() != fTable || fk.Name() != fkName {
+			k := dbmodel.NewForeignKey(fSchema, fTable, fkName)
+			fk = &k
+			if tbl, ok := tblMap[fTable]; ok && fSchema == schema {
+				tbl.AddForeignKey(fk)
+			}
+			if tbl, ok := tblMap[tTable]; ok && tSchema == schema {
+				tbl.AddReferencedKey(fk)
+			}
+		}
+		ref := dbmodel.NewColumnReference(findOrStubColumn(colMap, fSchema, fTable, fCol), findOrStubColumn(colMap, tSchema, tTable, tCol))
+		fk.AddColumnReference(&ref)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestMysqlDataSourceName(t *testing.T) {
+	cfg := &Config{
+		Driver:   "mysql",
+		Host:     "localhost",
+		Port:     3307,
+		User:     "root",
+		Password: "secret",
+		Database: "sample",
+	}
+	if a, e := mysqlDataSourceName(cfg), "root:secret@tcp(localhost:3307)/sample"; a != e {
+		t.Errorf("Invalid data source name. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlDataSourceNameWithDefaultPort(t *testing.T) {
+	cfg := &Config{
+		Driver:   "mysql",
+		Host:     "localhost",
+		User:     "root",
+		Password: "secret",
+		Database: "sample",
+	}
+	if a, e := mysqlDataSourceName(cfg), "root:secret@tcp(localhost:3306)/sample"; a != e {
+		t.Errorf("Port should be 3306 when port is not given. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlDataSourceNameWithOptions(t *testing.T) {
+	cfg := &Config{
+		Driver:   "mysql",
+		Host:     "localhost",
+		Port:     3306,
+		User:     "root",
+		Password: "secret",
+		Database: "sample",
+		Options: map[string]string{
+			"tls":     "skip-verify",
+			"charset": "utf8mb4",
+		},
+	}
+	if a, e := mysqlDataSourceName(cfg), "root:secret@tcp(localhost:3306)/sample?charset=utf8mb4&tls=skip-verify"; a != e {
+		t.Errorf("Options should be appended as sorted parameters. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestMysqlCheckConstraintSupport(t *testing.T) {
+	tests := map[string][2]bool{
+		"5.7":             {false, true},
+		"8.0":             {false, false},
+		"8.0.15":          {false, true},
+		"8.0.16":          {true, true},
+		"8.0.35-log":      {true, true},
+		"8.4":             {true, true},
+		"10.1":            {false, true},
+		"10.2":            {false, false},
+		"10.2.21-MariaDB": {false, true},
+		"10.2.22-MariaDB": {true, true},
+		"10.11":           {true, true},
+		"":                {false, false},
+		"latest":          {false, false},
+	}
+	for v, e := range tests {
+		supported, known := checkConstraintSupport(v)
+		if a := [2]bool{supported, known}; a != e {
+			t.Errorf("Invalid check constraint support on version %q. expected: %v, actual: %v", v, e, a)
+		}
+	}
+}
+
+func TestMysqlColumnDefaultQuoting(t *testing.T) {
+	tests := map[string][2]bool{
+		"8.0.35":         {false, true},
+		"10.2.6-MariaDB": {false, true},
+		"10.2.7-MariaDB": {true, true},
+		"10.6":           {true, true},
+		"10.2":           {false, false},
+		"":               {false, false},
+	}
+	for v, e := range tests {
+		quoted, known := columnDefaultQuoting(v)
+		if a := [2]bool{quoted, known}; a != e {
+			t.Errorf("Invalid column default quoting on version %q. expected: %v, actual: %v", v, e, a)
+		}
+	}
+}
+
+func TestMysqlColumnDefault(t *testing.T) {
+	tests := []struct {
+		defVal   sql.NullString
+		quoted   bool
+		expected string
+	}{
+		{sql.NullString{}, false, ""},
+		{sql.NullString{String: "NULL", Valid: true}, false, "NULL"},
+		{sql.NullString{String: "it's", Valid: true}, false, "it's"},
+		{sql.NullString{String: "NULL", Valid: true}, true, ""},
+		{sql.NullString{String: "'it''s'", Valid: true}, true, "it's"},
+		{sql.NullString{String: `'C:\\tmp'`, Valid: true}, true, `C:\tmp`},
+		{sql.NullString{String: "0", Valid: true}, true, "0"},
+		{sql.NullString{String: "current_timestamp()", Valid: true}, true, "current_timestamp()"},
+	}
+	for _, test := range tests {
+		if a := mysqlColumnDefault(test.defVal, test.quoted); a != test.expected {
+			t.Errorf("Invalid default value of %+v. expected: %v, actual: %v", test, test.expected, a)
+		}
+	}
+}
+
+func TestMysqlColumnType(t *testing.T) {
+	if a, e := mysqlColumnType("int unsigned", "auto_increment"), "int unsigned auto_increment"; a != e {
+		t.Errorf("Type should have auto_increment. expected: %v, actual: %v", e, a)
+	}
+	if a, e := mysqlColumnType("timestamp", "DEFAULT_GENERATED on update CURRENT_TIMESTAMP"), "timestamp"; a != e {
+		t.Errorf("Type should not be changed. expected: %v, actual: %v", e, a)
+	}
+}
+
+// TestMysqlSourceLoad loads catalog of local mysqld, and it is skipped when mysqld is not running.
+func TestMysqlSourceLoad(t *testing.T) {
+	db, err := sql.Open("mysql", "root@tcp(localhost:3306)/?multiStatements=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		t.Skipf("mysqld is not available: %v", err)
+	}
+	_, err = db.Exec(`
+DROP DATABASE IF EXISTS tablarian_test;
+CREATE DATABASE tablarian_test;
+CREATE TABLE tablarian_test.users (
+  id int unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  name varchar(50) NOT NULL DEFAULT 'it''s',
+  active tinyint(1) NOT NULL DEFAULT 1,
+  note text,
+  PRIMARY KEY (id),
+  UNIQUE KEY users_name_idx (name)
+) COMMENT 'Users';
+CREATE TABLE tablarian_test.posts (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  user_id int unsigned NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT posts_user_id_fkey FOREIGN KEY (user_id) REFERENCES tablarian_test.users (id)
+);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP DATABASE IF EXISTS tablarian_test")
+
+	src, err := newMysqlSource(&Config{Driver: "mysql", Host: "localhost", User: "root", Database: "tablarian_test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	users, err := src.Table("", "users", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, e := users.Comment(), "Users"; a != e {
+		t.Errorf("Invalid table comment. expected: %v, actual: %v", e, a)
+	}
+	conv := findConverter(true, "mysql")
+	expected := [][]string{
+		{"1", "id", "int unsigned auto_increment", "", "NO", "", "ID"},
+		{"", "name", "varchar", "50", "NO", "it's", ""},
+		{"", "active", "boolean", "", "NO", "1", ""},
+		{"", "note", "text", "", "", "", ""},
+	}
+	for i, col := range users.Columns() {
+		if e, a := strings.Join(expected[i], "|"), strings.Join(conv.ConvertColumn(col), "|"); e != a {
+			t.Errorf("Invalid column. expected: %v, actual: %v", e, a)
+		}
+	}
+	names := make([]string, 0)
+	for _, idx := range users.Indices() {
+		names = append(names, idx.Name())
+	}
+	if e, a := "PRIMARY, users_name_idx", strings.Join(names, ", "); e != a {
+		t.Errorf("Invalid indices. expected: %v, actual: %v", e, a)
+	}
+	if len(users.ReferencedKeys()) != 1 || users.ReferencedKeys()[0].Name() != "posts_user_id_fkey" {
+		t.Errorf("users should be referenced by posts. actual: %v", users.ReferencedKeys())
+	}
+}
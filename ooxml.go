@@ -0,0 +1,4 @@
+package main
+
+// ooxmlHeader is XML declaration of parts in Office Open XML package, which is shared by xlsx and docx.
+const ooxmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
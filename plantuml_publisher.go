@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// plantUMLPublisher writes ER diagram as PlantUML.
+type plantUMLPublisher struct {
+	basePublisher
+}
+
+func newPlantUMLPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *plantUMLPublisher {
+	return &plantUMLPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *plantUMLPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	tbls, err := diagramTables(tables, p.cfg.Diagram)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write("er.puml", convertToPlantUML(tbls, p.conv))
+}
+
+// convertToPlantUML makes entity blocks and relationship lines in information engineering notation.
+func convertToPlantUML(tables []*dbmodel.Table, conv Converter) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "@startuml")
+	fmt.Fprintln(buf, "hide circle")
+	fmt.Fprintln(buf, "skinparam linetype ortho")
+
+	for _, tbl := range tables {
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "entity \"%s\" as %s {\n", tbl.Name(), diagramAlias(tbl.Name()))
+		pks, others := splitPrimaryKeyColumns(tbl)
+		for _, col := range pks {
+			fmt.Fprintln(buf, plantUMLColumn(tbl, col, conv))
+		}
+		if len(pks) > 0 && len(others) > 0 {
+			fmt.Fprintln(buf, "  --")
+		}
+		for _, col := range others {
+			fmt.Fprintln(buf, plantUMLColumn(tbl, col, conv))
+		}
+		fmt.Fprintln(buf, "}")
+	}
+
+	rels := diagramRelations(tables)
+	if len(rels) > 0 {
+		fmt.Fprintln(buf)
+	}
+	for _, rel := range rels {
+		parent := "||"
+		if rel.parentOptional {
+			parent = "|o"
+		}
+		child := "o|"
+		if rel.childMany {
+			child = "o{"
+		}
+		fmt.Fprintf(buf, "%s %s--%s %s : %s\n", diagramAlias(rel.parent.Name()), parent, child, diagramAlias(rel.child.Name()), rel.fk.Name())
+	}
+
+	fmt.Fprintln(buf, "@enduml")
+	return buf.Bytes()
+}
+
+// plantUMLColumn returns column line. Mandatory column is marked with '*'.
+func plantUMLColumn(tbl *dbmodel.Table, col *dbmodel.Column, conv Converter) string {
+	mark := "  "
+	if !col.IsNullable() {
+		mark = "  * "
+	}
+	stereotypes := ""
+	if col.PrimaryKeyPosition() > 0 {
+		stereotypes += " <<PK>>"
+	}
+	if isForeignKeyColumn(tbl, col) {
+		stereotypes += " <<FK>>"
+	}
+	return fmt.Sprintf("%s%s : %s%s", mark, col.Name(), diagramColumnType(conv, col), stereotypes)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const diagramTestDDL = `
+CREATE TABLE shops (id serial PRIMARY KEY, name varchar(50) NOT NULL);
+CREATE TABLE staffs (id serial PRIMARY KEY, shop_id int REFERENCES shops (id), name text NOT NULL);
+CREATE TABLE staff_profiles (staff_id int PRIMARY KEY REFERENCES staffs (id), note text);
+CREATE TABLE orders (id serial PRIMARY KEY, shop_id int NOT NULL REFERENCES shops (id));
+CREATE TABLE order_items (order_id int REFERENCES orders (id), line int, PRIMARY KEY (order_id, line));
+CREATE TABLE settings (code text PRIMARY KEY, value text);
+`
+
+func TestConvertToPlantUML(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL)
+	actual := string(convertToPlantUML(tables, findConverter(true, "postgres")))
+	expected := `@startuml
+hide circle
+skinparam linetype ortho
+
+entity "order_items" as order_items {
+  * order_id : integer <<PK>> <<FK>>
+  * line : integer <<PK>>
+}
+
+entity "orders" as orders {
+  * id : serial <<PK>>
+  --
+  * shop_id : integer <<FK>>
+}
+
+entity "settings" as settings {
+  * code : text <<PK>>
+  --
+  value : text
+}
+
+entity "shops" as shops {
+  * id : serial <<PK>>
+  --
+  * name : varchar(50)
+}
+
+entity "staff_profiles" as staff_profiles {
+  * staff_id : integer <<PK>> <<FK>>
+  --
+  note : text
+}
+
+entity "staffs" as staffs {
+  * id : serial <<PK>>
+  --
+  shop_id : integer <<FK>>
+  * name : text
+}
+
+orders ||--o{ order_items : order_items_order_id_fkey
+shops ||--o{ orders : orders_shop_id_fkey
+staffs ||--o| staff_profiles : staff_profiles_staff_id_fkey
+shops |o--o{ staffs : staffs_shop_id_fkey
+@enduml
+`
+	if expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestDiagramTables(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL)
+	tests := []struct {
+		dc       DiagramConfig
+		expected string
+	}{
+		{DiagramConfig{}, "order_items,orders,settings,shops,staff_profiles,staffs"},
+		{DiagramConfig{Tables: []string{"shops", "settings"}}, "settings,shops"},
+		{DiagramConfig{Focus: "orders"}, "order_items,orders,shops"},
+		{DiagramConfig{Focus: "orders", Hops: 2}, "order_items,orders,shops,staffs"},
+		{DiagramConfig{Focus: "orders", Hops: 3}, "order_items,orders,shops,staff_profiles,staffs"},
+		{DiagramConfig{Tables: []string{"settings"}, Focus: "staff_profiles"}, "settings,staff_profiles,staffs"},
+	}
+	for _, test := range tests {
+		tbls, err := diagramTables(tables, test.dc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, 0, len(tbls))
+		for _, tbl := range tbls {
+			names = append(names, tbl.Name())
+		}
+		if actual := strings.Join(names, ","); test.expected != actual {
+			t.Errorf("expected: %v, actual: %v", test.expected, actual)
+		}
+	}
+}
+
+func TestDiagramTablesWithUnknownTable(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL)
+	for _, dc := range []DiagramConfig{{Tables: []string{"foo"}}, {Focus: "foo"}} {
+		_, err := diagramTables(tables, dc)
+		if err == nil {
+			t.Fatal("Unknown table should raise error.")
+		}
+		if expected, actual := "Table 'foo' is not found.", err.Error(); expected != actual {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestCmdPublishPlantUML(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "plantuml"
+	publishOpt.prettyPrint = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	b, err := ioutil.ReadFile(filepath.Join(dir, "er.puml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := "users ||--o{ posts : posts_user_id_fkey\n@enduml\n"; !strings.HasSuffix(string(b), s) {
+		t.Errorf("er.puml should end with %s, actual:\n%s", s, string(b))
+	}
+}
+
+func TestCmdPublishMarkdownWithPlantUML(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	publishOpt.locale = "ja"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-diagram")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	b, err := ioutil.ReadFile(filepath.Join(dir, "00_index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := "\n## ER図\n\n```plantuml\n@startuml\n"; !strings.Contains(string(b), s) {
+		t.Errorf("00_index.md should contain %s, actual:\n%s", s, string(b))
+	}
+	if s := "users ||--o{ posts : posts_user_id_fkey\n@enduml\n```\n"; !strings.HasSuffix(string(b), s) {
+		t.Errorf("00_index.md should end with %s, actual:\n%s", s, string(b))
+	}
+}
@@ -57,8 +57,11 @@ func (ppc postgresPrettyConverter) ConvertColumn(col *dbmodel.Column) []string {
 }
 
 func isSerial(col *dbmodel.Column) bool {
-	tblName := col.TableName()
-	colName := col.Name()
+	return col.DefaultValue() == fmt.Sprintf("nextval('%s'::regclass)", postgresSequenceName(col.TableName(), col.Name()))
+}
+
+// postgresSequenceName returns sequence name that PostgreSQL makes for serial column.
+func postgresSequenceName(tblName string, colName string) string {
 	if len(tblName)+len(colName)+5 > maxSeqLength {
 		if len(tblName) > threshold {
 			if len(colName) > threshold {
@@ -73,5 +76,5 @@ func isSerial(col *dbmodel.Column) bool {
 			colName = colName[0:len]
 		}
 	}
-	return col.DefaultValue() == fmt.Sprintf("nextval('%s_%s_seq'::regclass)", tblName, colName)
+	return fmt.Sprintf("%s_%s_seq", tblName, colName)
 }
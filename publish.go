@@ -3,15 +3,19 @@ package main
 import (
 	"fmt"
 	"io"
-
-	"github.com/pinzolo/dbmodel"
+	"path/filepath"
 )
 
 type publishOption struct {
 	baseOption
-	format  string
-	locale  string
-	verbose bool
+	format     string
+	locale     string
+	verbose    bool
+	check      bool
+	diagram    string
+	svg        bool
+	delimiter  string
+	singleFile bool
 }
 
 var (
@@ -26,14 +30,73 @@ Options:
         use config file instead of default config file(.tablarian.config)
         if CONFIG_FILE starts with '@', it is treated as absolute file path.
 
+    --ddl DDL_FILE
+        load table definitions from DDL_FILE instead of connecting to database.
+        driver in config file is used as SQL dialect.
+        unsupported statements are skipped with warning.
+        if DDL_FILE starts with '@', it is treated as absolute file path.
+
+    --migrations MIGRATIONS_DIR
+        load table definitions by applying up-migrations in MIGRATIONS_DIR in order of version.
+        Flyway(V1__name.sql), golang-migrate(1_name.up.sql) and goose(1_name.sql) file names are acceptable.
+        if MIGRATIONS_DIR starts with '@', it is treated as absolute file path.
+
+    --until VERSION
+        apply migrations until VERSION. (use with --migrations)
+
+    --snapshot SNAPSHOT_FILE
+        load table definitions from SNAPSHOT_FILE made by dump command instead of connecting to database.
+        if SNAPSHOT_FILE starts with '@', it is treated as absolute file path.
+
     -p, --pretty
         convert data type to usually name.
-        this option is author's personal option. (only PostgreSQL)
+        this option is author's personal option. (PostgreSQL and MySQL)
 
     -f, --format
         file format for saving table definitions.
         formats:
             markdown (default)
+            html (static site with search, no network access is required)
+            xlsx (workbook that has index sheet and sheet per table)
+            plantuml (ER diagram)
+            dot (relationship graph for Graphviz)
+            dbml (DBML for dbdiagram.io and other tools)
+            json (index and table documents for other tools, described by tablarian.schema.json)
+            yaml (same documents as json in YAML)
+            csv (tables.csv, columns.csv, indices.csv and foreign_keys.csv for spreadsheets and BI tools)
+            asciidoc (pages in tables directory and index.adoc that includes all pages for Asciidoctor)
+            rst (reStructuredText pages in tables directory and index.rst with toctree for Sphinx)
+            docx (Word document that has cover page, table of contents and chapter per table)
+            confluence (pages in Confluence storage format in tables directory, index.xml and manifest.json of page tree)
+            textile (wiki pages in tables directory and index.textile for Redmine and Backlog)
+            latex (tables.tex book for lualatex)
+            template (pages by text/template files of 'templates' in config file, markdown by default)
+                'table' and 'index' of 'templates' are template files, and 'ext' is extension of pages. (default: .md)
+                templates get .Table and .Tables, which have fields of json documents and .Rows made by converter.
+                functions: t, headers, row, markdownTable, page, anchor, join and escape.
+
+    --diagram KIND
+        embed ER diagram of KIND in markdown. (overrides 'markdown' of 'diagram' in config file)
+        index has diagram of all tables, and table page has diagram of the table and its neighbors.
+        tables in diagrams are limited by 'tables', 'focus' and 'hops' of 'diagram' in config file.
+        kinds:
+            mermaid
+            plantuml
+            none
+
+    --svg
+        write ER diagram as er.svg and neighborhood diagram of each table under er directory. (markdown and html)
+        tables in diagrams are limited by 'tables', 'focus' and 'hops' of 'diagram' in config file.
+        this option is same as 'svg' of 'diagram' in config file.
+
+    --single-file
+        write index and all table pages into SCHEMA.md that has table of contents. (markdown)
+        links to pages are replaced with links to headings in SCHEMA.md.
+        this option is same as 'single_file' of 'markdown' in config file.
+
+    --delimiter DELIMITER
+        use DELIMITER instead of ',' in csv format. (overrides 'delimiter' of 'csv' in config file)
+        'tab' writes TSV files (*.tsv).
 
     -l LOCALE, --locale LOCALE
         use LOCALE instead of default locale(en).
@@ -41,6 +104,10 @@ Options:
 
     -v, --verbose
         print verbose log to console.
+
+    --check
+        not write files, but compare published files with files in output directory.
+        files that would be created, changed or removed are printed, and exit code is 1 when they exist.
 	`,
 	}
 	publishOpt = publishOption{}
@@ -49,14 +116,23 @@ Options:
 func init() {
 	cmdPublish.Flag.StringVar(&publishOpt.configFile, "config", DefaultConfigFileName, "Config file path")
 	cmdPublish.Flag.StringVar(&publishOpt.configFile, "c", DefaultConfigFileName, "Config file path")
+	cmdPublish.Flag.StringVar(&publishOpt.ddlFile, "ddl", "", "DDL file path")
+	cmdPublish.Flag.StringVar(&publishOpt.migrationsDir, "migrations", "", "Migrations directory path")
+	cmdPublish.Flag.StringVar(&publishOpt.until, "until", "", "Migration version")
+	cmdPublish.Flag.StringVar(&publishOpt.snapshotFile, "snapshot", "", "Snapshot file path")
 	cmdPublish.Flag.BoolVar(&publishOpt.prettyPrint, "pretty", false, "Pretty print")
 	cmdPublish.Flag.BoolVar(&publishOpt.prettyPrint, "p", false, "Pretty print")
 	cmdPublish.Flag.StringVar(&publishOpt.format, "format", "markdown", "File format")
 	cmdPublish.Flag.StringVar(&publishOpt.format, "f", "markdown", "File format")
+	cmdPublish.Flag.StringVar(&publishOpt.diagram, "diagram", "", "Diagram in markdown")
+	cmdPublish.Flag.BoolVar(&publishOpt.svg, "svg", false, "Write SVG diagrams")
+	cmdPublish.Flag.StringVar(&publishOpt.delimiter, "delimiter", "", "Delimiter of csv")
+	cmdPublish.Flag.BoolVar(&publishOpt.singleFile, "single-file", false, "Write markdown into single file")
 	cmdPublish.Flag.StringVar(&publishOpt.locale, "locale", "en", "Locale")
 	cmdPublish.Flag.StringVar(&publishOpt.locale, "l", "en", "Locale")
 	cmdPublish.Flag.BoolVar(&publishOpt.verbose, "v", false, "Print log")
 	cmdPublish.Flag.BoolVar(&publishOpt.verbose, "verbose", false, "Print log")
+	cmdPublish.Flag.BoolVar(&publishOpt.check, "check", false, "Check output directory is up to date")
 }
 
 // runPublish executes out command and return exit code.
@@ -67,21 +143,50 @@ func runPublish(args []string) int {
 		return 1
 	}
 
-	db := dbClientFor(cfg)
-	db.Connect()
-	defer db.Disconnect()
+	src, err := findSource(cfg, publishOpt.baseOption)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	defer src.Close()
 
-	tables, err := db.AllTables(cfg.Schema, dbmodel.RequireAll)
+	tables, err := src.AllTables(cfg.Schema)
 	if err != nil {
 		fmt.Fprintln(o.err, err)
 		return 1
 	}
+	switch publishOpt.diagram {
+	case "":
+	case "none":
+		cfg.Diagram.Markdown = ""
+	default:
+		cfg.Diagram.Markdown = publishOpt.diagram
+	}
+	if publishOpt.svg {
+		cfg.Diagram.SVG = true
+	}
+	if publishOpt.delimiter != "" {
+		cfg.CSV.Delimiter = publishOpt.delimiter
+	}
+	if publishOpt.singleFile {
+		cfg.Markdown.SingleFile = true
+	}
 	conv := findConverter(publishOpt.prettyPrint, cfg.Driver)
 	var logger io.Writer
-	if publishOpt.verbose {
+	if publishOpt.verbose && !publishOpt.check {
 		logger = o.out
 	}
-	pub, err := findPublisher(publishOpt.format, cfg, conv, l(publishOpt.locale), logger)
+	dir, err := newDirOutput(cfg)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	var out publishOutput = dir
+	mem := newMemoryOutput()
+	if publishOpt.check {
+		out = mem
+	}
+	pub, err := findPublisher(publishOpt.format, cfg, conv, l(publishOpt.locale), out, logger)
 	if err != nil {
 		fmt.Fprintln(o.err, err)
 		return 1
@@ -95,5 +200,31 @@ func runPublish(args []string) int {
 		return 1
 	}
 
+	if publishOpt.check {
+		return checkPublished(mem, dir.path, cfg.Out)
+	}
 	return 0
 }
+
+// checkPublished prints files that are different from published files, and returns exit code.
+func checkPublished(mem *memoryOutput, path string, out string) int {
+	changes, err := mem.compare(path)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
+	}
+	if changes.isEmpty() {
+		fmt.Fprintf(o.out, "%s is up to date.\n", out)
+		return 0
+	}
+	for _, name := range changes.created {
+		fmt.Fprintln(o.out, "Created:", filepath.Join(out, name))
+	}
+	for _, name := range changes.changed {
+		fmt.Fprintln(o.out, "Changed:", filepath.Join(out, name))
+	}
+	for _, name := range changes.removed {
+		fmt.Fprintln(o.out, "Removed:", filepath.Join(out, name))
+	}
+	return 1
+}
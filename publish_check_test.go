@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func publishWithMigrations(check bool) (int, string) {
+	initPublishOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	publishOpt.check = check
+	stat := cmdPublish.Run([]string{})
+	return stat, buf.String()
+}
+
+func TestCmdPublishCheckUpToDate(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	if stat, _ := publishWithMigrations(false); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+
+	stat, out := publishWithMigrations(true)
+	if stat != 0 {
+		t.Errorf("expected: %v, actual: %v", 0, stat)
+	}
+	if expected, actual := "out is up to date.\n", out; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdPublishCheckWithoutOutDir(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	stat, out := publishWithMigrations(true)
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	expected := `Created: out/00_index.md
+Created: out/posts.md
+Created: out/users.md
+`
+	if actual := out; expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	path, _ := resolvePath("out")
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Publish subcommand with --check should not make output directory.")
+	}
+}
+
+func TestCmdPublishCheckWithDifferences(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	if stat, _ := publishWithMigrations(false); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	path, _ := resolvePath("out")
+	os.Remove(filepath.Join(path, "posts.md"))
+	ioutil.WriteFile(filepath.Join(path, "users.md"), []byte("# users\n"), 0644)
+	ioutil.WriteFile(filepath.Join(path, "old.md"), []byte("# old\n"), 0644)
+
+	stat, out := publishWithMigrations(true)
+	if stat != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, stat)
+	}
+	expected := `Created: out/posts.md
+Changed: out/users.md
+Removed: out/old.md
+`
+	if actual := out; expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(path, "users.md")); string(b) != "# users\n" {
+		t.Error("Publish subcommand with --check should not change files.")
+	}
+	if _, err := os.Stat(filepath.Join(path, "old.md")); err != nil {
+		t.Error("Publish subcommand with --check should not remove files.")
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readDocx reads all parts of document, and checks that they are well-formed XML.
+func readDocx(t *testing.T, path string) map[string][]byte {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err = dec.Token(); err != nil {
+				break
+			}
+		}
+		if err != io.EOF {
+			t.Errorf("%s is invalid XML: %v", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/document.xml", "word/_rels/document.xml.rels", "word/styles.xml", "word/settings.xml", "docProps/core.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s should exist in document", name)
+		}
+	}
+	return files
+}
+
+// docxOutline returns paragraphs as 'style: text' and table rows as cells joined by '|'.
+// Header row is prefixed by 'H:', and page break is 'PAGE BREAK'.
+func docxOutline(t *testing.T, document []byte) []string {
+	dec := xml.NewDecoder(bytes.NewReader(document))
+	lines := make([]string, 0)
+	var style, text string
+	var cells []string
+	depth, header := 0, false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch e := tok.(type) {
+		case xml.StartElement:
+			switch e.Name.Local {
+			case "tbl":
+				depth++
+			case "tr":
+				cells, header = make([]string, 0), false
+			case "tblHeader":
+				header = true
+			case "p":
+				style, text = "", ""
+			case "pStyle":
+				style = e.Attr[0].Value
+			case "br":
+				if len(e.Attr) > 0 && e.Attr[0].Value == "page" {
+					lines = append(lines, "PAGE BREAK")
+				} else {
+					text += "\n"
+				}
+			case "instrText":
+				var s string
+				dec.DecodeElement(&s, &e)
+				lines = append(lines, "FIELD:"+strings.TrimSpace(s))
+			case "t":
+				var s string
+				dec.DecodeElement(&s, &e)
+				text += s
+			}
+		case xml.EndElement:
+			switch e.Name.Local {
+			case "tbl":
+				depth--
+			case "tr":
+				prefix := ""
+				if header {
+					prefix = "H:"
+				}
+				lines = append(lines, prefix+strings.Join(cells, "|"))
+			case "p":
+				if depth > 0 {
+					cells = append(cells, text)
+				} else if style != "" || text != "" {
+					lines = append(lines, style+": "+text)
+				}
+			}
+		}
+	}
+	return lines
+}
+
+func TestCmdPublishDocx(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "docx"
+	publishOpt.locale = "ja"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	if expected, actual := "Created: out/tables.docx\n", strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	files := readDocx(t, filepath.Join(dir, "tables.docx"))
+
+	expected := []string{
+		"Title: テーブル定義書",
+		"PAGE BREAK",
+		"TOCHeading: 目次",
+		`FIELD:TOC \o "1-1" \h \z \u`,
+		"TOC1: posts",
+		"TOC1: users",
+		"Heading1: posts",
+		": Posts of users",
+		"Heading2: 列一覧",
+		"H:PK|列名|型|サイズ|NULL|初期値|コメント",
+		"1|id|int4|32, 0|NO|nextval('posts_id_seq'::regclass)|",
+		"|user_id|int4|32, 0|NO||",
+		"|title|text||NO||",
+		"Heading2: インデックス",
+		"H:名前|列|ユニーク",
+		"posts_pkey|id|YES",
+		"Heading2: 参照キー",
+		"H:参照名|列|参照テーブル|参照列",
+		"posts_user_id_fkey|user_id|users|id",
+		"Heading1: users",
+		"Heading2: 列一覧",
+		"H:PK|列名|型|サイズ|NULL|初期値|コメント",
+		"1|id|int4|32, 0|NO|nextval('users_id_seq'::regclass)|",
+		"|display_name|varchar|50|NO||",
+		"|email|varchar|100|||",
+		"Heading2: インデックス",
+		"H:名前|列|ユニーク",
+		"users_email_idx|email|YES",
+		"users_pkey|id|YES",
+		"Heading2: 被参照キー",
+		"H:参照名|参照元テーブル|参照元列|被参照列",
+		"posts_user_id_fkey|posts|user_id|id",
+	}
+	if actual := docxOutline(t, files["word/document.xml"]); strings.Join(expected, "\n") != strings.Join(actual, "\n") {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+
+	document := string(files["word/document.xml"])
+	for _, s := range []string{
+		`<w:hyperlink w:anchor="_Toc1" w:history="1">`,
+		`<w:bookmarkStart w:id="1" w:name="_Toc2"/>`,
+		`<w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="D9E2F3"/></w:tcPr><w:p><w:r><w:rPr><w:b/></w:rPr>`,
+	} {
+		if !strings.Contains(document, s) {
+			t.Errorf("document.xml should contain %s", s)
+		}
+	}
+	if core := string(files["docProps/core.xml"]); !strings.Contains(core, "<dc:title>テーブル定義書</dc:title>") {
+		t.Errorf("core.xml should have title, actual: %s", core)
+	}
+}
+
+func TestDocxRun(t *testing.T) {
+	expected := `<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">a &lt;b&gt;</w:t><w:br/><w:t xml:space="preserve">c</w:t></w:r>`
+	if actual := docxRun("a <b>\nc", true); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func publishHTMLWithMigrations(locale string) (int, string, error) {
+	if err := initPublishMarkdownTest(); err != nil {
+		return 0, "", err
+	}
+	publishOpt.format = "html"
+	publishOpt.locale = locale
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	stat := cmdPublish.Run([]string{})
+	path, err := resolvePath("out")
+	return stat, path, err
+}
+
+func readPublished(t *testing.T, dir string, name string) string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCmdPublishHTML(t *testing.T) {
+	stat, dir, err := publishHTMLWithMigrations("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	expected := `Created: out/tables/posts.html
+Created: out/tables/users.html
+Created: out/index.html
+Created: out/search_index.json
+Created: out/search_index.js
+Created: out/assets/style.css
+Created: out/assets/search.js
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	idx := readPublished(t, dir, "index.html")
+	for _, s := range []string{
+		"<title>Table index</title>",
+		`<tr><th>TABLE</th><th>COMMENT</th></tr>`,
+		`<tr><td><a href="tables/posts.html">posts</a></td><td>Posts of users</td></tr>`,
+		`<tr><td><a href="tables/users.html">users</a></td><td></td></tr>`,
+		`<input type="search" id="search" placeholder="Search" data-root="">`,
+		`<link rel="stylesheet" href="assets/style.css">`,
+	} {
+		if !strings.Contains(idx, s) {
+			t.Errorf("index.html should contain %s", s)
+		}
+	}
+
+	posts := readPublished(t, dir, "tables/posts.html")
+	for _, s := range []string{
+		"<h1>posts</h1>",
+		`<p class="comment">Posts of users</p>`,
+		"<h2>Columns</h2>",
+		`<tr id="column-user_id"><td></td><td>user_id</td><td>int4</td><td>32, 0</td><td>NO</td><td></td><td></td></tr>`,
+		"<h2>Foreign keys</h2>",
+		`<tr><td>posts_user_id_fkey</td><td>user_id</td><td><a href="../tables/users.html">users</a></td><td>id</td></tr>`,
+		`<li class="current"><a href="../tables/posts.html">posts</a></li>`,
+		`<script src="../search_index.js"></script>`,
+		`<link rel="stylesheet" href="../assets/style.css">`,
+		`data-root="../"`,
+	} {
+		if !strings.Contains(posts, s) {
+			t.Errorf("posts.html should contain %s", s)
+		}
+	}
+
+	users := readPublished(t, dir, "tables/users.html")
+	if s := `<tr><td>posts_user_id_fkey</td><td><a href="../tables/posts.html">posts</a></td><td>user_id</td><td>id</td></tr>`; !strings.Contains(users, s) {
+		t.Errorf("users.html should contain %s", s)
+	}
+	if strings.Contains(users, "https://") || strings.Contains(users, "http://") {
+		t.Error("users.html should not refer external resources.")
+	}
+
+	var entries []htmlSearchEntry
+	if err := json.Unmarshal([]byte(readPublished(t, dir, "search_index.json")), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 8 {
+		t.Errorf("expected: %v, actual: %v", 8, len(entries))
+	}
+	expectedEntry := htmlSearchEntry{Table: "posts", Comment: "Posts of users", URL: "tables/posts.html"}
+	if entries[0] != expectedEntry {
+		t.Errorf("expected: %v, actual: %v", expectedEntry, entries[0])
+	}
+	expectedEntry = htmlSearchEntry{Table: "users", Column: "email", URL: "tables/users.html#column-email"}
+	if entries[7] != expectedEntry {
+		t.Errorf("expected: %v, actual: %v", expectedEntry, entries[7])
+	}
+	if js := readPublished(t, dir, "search_index.js"); !strings.HasPrefix(js, "var searchIndex = [") {
+		t.Errorf("search_index.js should define searchIndex, actual: %v", js)
+	}
+}
+
+func TestCmdPublishHTMLWithLocale(t *testing.T) {
+	stat, dir, err := publishHTMLWithMigrations("ja")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	users := readPublished(t, dir, "tables/users.html")
+	for _, s := range []string{
+		"<h2>列一覧</h2>",
+		"<th>列名</th>",
+		"<h2>被参照キー</h2>",
+		`<a href="../index.html">テーブル一覧</a>`,
+		`placeholder="検索"`,
+	} {
+		if !strings.Contains(users, s) {
+			t.Errorf("users.html should contain %s", s)
+		}
+	}
+}
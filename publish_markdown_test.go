@@ -392,6 +392,108 @@ func TestCmdPublishWithInvalidFormat(t *testing.T) {
 	}
 }
 
+func TestCmdPublishMarkdownSingleFile(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.singleFile = true
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	if expected, actual := "Created: out/SCHEMA.md\n", strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	expected := `# Table definitions
+
+## Contents
+
+- [Table index](#table-index)
+- [posts](#posts)
+- [users](#users)
+
+# Table index
+
+|      TABLE      |    COMMENT     |
+|-----------------|----------------|
+| [posts](#posts) | Posts of users |
+| [users](#users) |                |
+
+# posts
+
+Posts of users
+
+## Columns
+
+| PK |  NAME   | TYPE | SIZE  | NULL |              DEFAULT              | COMMENT |
+|----|---------|------|-------|------|-----------------------------------|---------|
+|  1 | id      | int4 | 32, 0 | NO   | nextval('posts_id_seq'::regclass) |         |
+|    | user_id | int4 | 32, 0 | NO   |                                   |         |
+|    | title   | text |       | NO   |                                   |         |
+
+## Indices
+
+|    NAME    | COLUMNS | UNIQUE |
+|------------|---------|--------|
+| posts_pkey | id      | YES    |
+
+## Foreign keys
+
+|        NAME        | COLUMNS |  FOREIGN TABLE  | FOREIGN COLUMNS |
+|--------------------|---------|-----------------|-----------------|
+| posts_user_id_fkey | user_id | [users](#users) | id              |
+
+# users
+
+## Columns
+
+| PK |     NAME     |  TYPE   | SIZE  | NULL |              DEFAULT              | COMMENT |
+|----|--------------|---------|-------|------|-----------------------------------|---------|
+|  1 | id           | int4    | 32, 0 | NO   | nextval('users_id_seq'::regclass) |         |
+|    | display_name | varchar |    50 | NO   |                                   |         |
+|    | email        | varchar |   100 |      |                                   |         |
+
+## Indices
+
+|      NAME       | COLUMNS | UNIQUE |
+|-----------------|---------|--------|
+| users_email_idx | email   | YES    |
+| users_pkey      | id      | YES    |
+
+## Referenced keys
+
+|        NAME        |  SOURCE TABLE   | SOURCE COLUMNS | COLUMNS |
+|--------------------|-----------------|----------------|---------|
+| posts_user_id_fkey | [posts](#posts) | user_id        | id      |
+`
+	if actual := readPublished(t, dir, "SCHEMA.md"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestMarkdownAnchors(t *testing.T) {
+	anchors := markdownAnchors{}
+	actual := make([]string, 0)
+	for _, h := range []string{"Columns", "columns", "Foreign keys", "Columns"} {
+		actual = append(actual, anchors.add(h))
+	}
+	if expected := []string{"columns", "columns-1", "foreign-keys", "columns-2"}; strings.Join(expected, ",") != strings.Join(actual, ",") {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestMarkdownHeadings(t *testing.T) {
+	md := "# users\n\nComment\n#not heading\n\n## Diagram\n\n```plantuml\n# in code\n```\n"
+	if expected, actual := "users,Diagram", strings.Join(markdownHeadings([]byte(md)), ","); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
 func initPublishMarkdownTest() error {
 	initPublishOpt()
 
@@ -413,6 +515,15 @@ func initPublishOpt() {
 	publishOpt.format = "markdown"
 	publishOpt.locale = "en"
 	publishOpt.verbose = false
+	publishOpt.check = false
+	publishOpt.diagram = ""
+	publishOpt.svg = false
+	publishOpt.delimiter = ""
+	publishOpt.singleFile = false
+	publishOpt.ddlFile = ""
+	publishOpt.migrationsDir = ""
+	publishOpt.until = ""
+	publishOpt.snapshotFile = ""
 }
 
 func isSameFile(path string, testFile string) bool {
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// publishOutput is destination of files made by publisher.
+type publishOutput interface {
+	// Prepare makes destination empty.
+	Prepare() error
+	// Write writes content as file. name is relative path from root of destination.
+	// It returns path of written file for log.
+	Write(name string, content []byte) (string, error)
+}
+
+// dirOutput writes files under directory.
+type dirOutput struct {
+	path string
+}
+
+func newDirOutput(config *Config) (*dirOutput, error) {
+	path, err := resolvePath(config.Out)
+	if err != nil {
+		return nil, err
+	}
+	return &dirOutput{path: path}, nil
+}
+
+func (d *dirOutput) Prepare() error {
+	return cleanDir(d.path)
+}
+
+func (d *dirOutput) Write(name string, content []byte) (string, error) {
+	path := filepath.Join(d.path, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, writeToFile(path, content)
+}
+
+// memoryOutput keeps files in memory.
+type memoryOutput struct {
+	files map[string][]byte
+}
+
+func newMemoryOutput() *memoryOutput {
+	return &memoryOutput{files: make(map[string][]byte)}
+}
+
+func (m *memoryOutput) Prepare() error {
+	m.files = make(map[string][]byte)
+	return nil
+}
+
+func (m *memoryOutput) Write(name string, content []byte) (string, error) {
+	m.files[filepath.ToSlash(name)] = content
+	return name, nil
+}
+
+// outputChanges is result of comparing published files with files in directory.
+type outputChanges struct {
+	created []string
+	changed []string
+	removed []string
+}
+
+func (c outputChanges) isEmpty() bool {
+	return len(c.created) == 0 && len(c.changed) == 0 && len(c.removed) == 0
+}
+
+// compare compares files in memory with files under directory. Names in result are slash separated relative paths.
+func (m *memoryOutput) compare(dir string) (outputChanges, error) {
+	c := outputChanges{}
+	existing := make(map[string]bool)
+	if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			existing[filepath.ToSlash(rel)] = true
+			return nil
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	for name, content := range m.files {
+		if !existing[name] {
+			c.created = append(c.created, name)
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return c, err
+		}
+		if !bytes.Equal(b, content) {
+			c.changed = append(c.changed, name)
+		}
+	}
+	for name := range existing {
+		if _, ok := m.files[name]; !ok {
+			c.removed = append(c.removed, name)
+		}
+	}
+	sort.Strings(c.created)
+	sort.Strings(c.changed)
+	sort.Strings(c.removed)
+	return c, nil
+}
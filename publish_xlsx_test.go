@@ -0,0 +1,186 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type xlsxTestWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxTestSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref   string `xml:"r,attr"`
+			Value string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	Hyperlinks []struct {
+		Ref      string `xml:"ref,attr"`
+		Location string `xml:"location,attr"`
+	} `xml:"hyperlinks>hyperlink"`
+}
+
+// readXlsx reads workbook and returns sheet names and sheets.
+func readXlsx(t *testing.T, path string) ([]string, []xlsxTestSheet) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = content
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s should exist in workbook", name)
+		}
+	}
+
+	wb := xlsxTestWorkbook{}
+	if err = xml.Unmarshal(files["xl/workbook.xml"], &wb); err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(wb.Sheets))
+	sheets := make([]xlsxTestSheet, 0, len(wb.Sheets))
+	for i, s := range wb.Sheets {
+		names = append(names, s.Name)
+		sheet := xlsxTestSheet{}
+		if err = xml.Unmarshal(files[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)], &sheet); err != nil {
+			t.Fatal(err)
+		}
+		sheets = append(sheets, sheet)
+	}
+	return names, sheets
+}
+
+// xlsxRowValues returns values of each rows that are joined by '|'.
+func xlsxRowValues(sheet xlsxTestSheet) []string {
+	rows := make([]string, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		values := make([]string, 0, len(r.Cells))
+		for _, c := range r.Cells {
+			values = append(values, c.Ref+"="+c.Value)
+		}
+		rows = append(rows, strings.Join(values, "|"))
+	}
+	return rows
+}
+
+func TestCmdPublishXlsx(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "xlsx"
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	names, sheets := readXlsx(t, filepath.Join(dir, "tables.xlsx"))
+
+	if expected, actual := "Table index,posts,users", strings.Join(names, ","); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	expected := []string{
+		"A1=Table index",
+		"",
+		"A3=TABLE|B3=COMMENT",
+		"A4=posts|B4=Posts of users",
+		"A5=users",
+	}
+	if actual := xlsxRowValues(sheets[0]); strings.Join(expected, "\n") != strings.Join(actual, "\n") {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+	links := sheets[0].Hyperlinks
+	if len(links) != 2 || links[0].Ref != "A4" || links[0].Location != "'posts'!A1" || links[1].Ref != "A5" || links[1].Location != "'users'!A1" {
+		t.Errorf("invalid hyperlinks: %v", links)
+	}
+
+	expected = []string{
+		"A1=users",
+		"",
+		"A3=Columns",
+		"A4=PK|B4=NAME|C4=TYPE|D4=SIZE|E4=NULL|F4=DEFAULT|G4=COMMENT",
+		"A5=1|B5=id|C5=int4|D5=32, 0|E5=NO|F5=nextval('users_id_seq'::regclass)",
+		"B6=display_name|C6=varchar|D6=50|E6=NO",
+		"B7=email|C7=varchar|D7=100",
+		"",
+		"A9=Indices",
+		"A10=NAME|B10=COLUMNS|C10=UNIQUE",
+		"A11=users_email_idx|B11=email|C11=YES",
+		"A12=users_pkey|B12=id|C12=YES",
+		"",
+		"A14=Referenced keys",
+		"A15=NAME|B15=SOURCE TABLE|C15=SOURCE COLUMNS|D15=COLUMNS",
+		"A16=posts_user_id_fkey|B16=posts|C16=user_id|D16=id",
+	}
+	if actual := xlsxRowValues(sheets[2]); strings.Join(expected, "\n") != strings.Join(actual, "\n") {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestXlsxSheetName(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     []string
+		expected string
+	}{
+		{"users", nil, "users"},
+		{"a/b[c]", nil, "a_b_c_"},
+		{"sales_order_header_sales_reason_history", nil, "sales_order_header_sales_reason"},
+		{"sales_order_header_sales_reason_history", []string{"sales_order_header_sales_reason"}, "sales_order_header_sales_rea(2)"},
+		{"Users", []string{"users"}, "Users(2)"},
+		{"テーブル一覧", nil, "テーブル一覧"},
+	}
+	for _, test := range tests {
+		if actual := xlsxSheetName(test.name, test.used); test.expected != actual {
+			t.Errorf("expected: %v, actual: %v", test.expected, actual)
+		}
+	}
+}
+
+func TestXlsxCellRef(t *testing.T) {
+	tests := []struct {
+		col      int
+		row      int
+		expected string
+	}{
+		{0, 0, "A1"},
+		{25, 1, "Z2"},
+		{26, 2, "AA3"},
+		{701, 3, "ZZ4"},
+		{702, 4, "AAA5"},
+	}
+	for _, test := range tests {
+		if actual := xlsxCellRef(test.col, test.row); test.expected != actual {
+			t.Errorf("expected: %v, actual: %v", test.expected, actual)
+		}
+	}
+}
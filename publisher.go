@@ -13,10 +13,81 @@ type Publisher interface {
 	Errors() []error
 }
 
-func findPublisher(format string, config *Config, converter Converter, locale locale, logger io.Writer) (Publisher, error) {
-	if format == "markdown" {
-		return newMarkdownPublisher(config, converter, locale, logger), nil
+func findPublisher(format string, config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) (Publisher, error) {
+	switch format {
+	case "markdown":
+		return newMarkdownPublisher(config, converter, locale, out, logger), nil
+	case "html":
+		return newHTMLPublisher(config, converter, locale, out, logger), nil
+	case "xlsx":
+		return newXlsxPublisher(config, converter, locale, out, logger), nil
+	case "plantuml":
+		return newPlantUMLPublisher(config, converter, locale, out, logger), nil
+	case "dot":
+		return newDotPublisher(config, converter, locale, out, logger), nil
+	case "dbml":
+		return newDBMLPublisher(config, converter, locale, out, logger), nil
+	case "json":
+		return newJSONPublisher(config, converter, locale, out, logger), nil
+	case "yaml":
+		return newYAMLPublisher(config, converter, locale, out, logger), nil
+	case "csv":
+		return newCSVPublisher(config, converter, locale, out, logger), nil
+	case "asciidoc":
+		return newAsciiDocPublisher(config, converter, locale, out, logger), nil
+	case "rst":
+		return newRSTPublisher(config, converter, locale, out, logger), nil
+	case "docx":
+		return newDocxPublisher(config, converter, locale, out, logger), nil
+	case "confluence":
+		return newConfluencePublisher(config, converter, locale, out, logger), nil
+	case "textile":
+		return newTextilePublisher(config, converter, locale, out, logger), nil
+	case "latex":
+		return newLaTeXPublisher(config, converter, locale, out, logger), nil
+	case "template":
+		return newTemplatePublisher(config, converter, locale, out, logger), nil
 	}
 
 	return nil, fmt.Errorf("Format '%s' is invalid format.", format)
 }
+
+// basePublisher has fields and helpers that are common to publishers.
+type basePublisher struct {
+	cfg    *Config
+	conv   Converter
+	loc    locale
+	out    publishOutput
+	logger io.Writer
+	errors []error
+}
+
+func newBasePublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) basePublisher {
+	return basePublisher{
+		cfg:    config,
+		conv:   converter,
+		loc:    locale,
+		out:    out,
+		logger: logger,
+		errors: make([]error, 0, 0),
+	}
+}
+
+func (p *basePublisher) Errors() []error {
+	return p.errors
+}
+
+func (p *basePublisher) write(name string, content []byte) {
+	path, err := p.out.Write(name, content)
+	if err == nil {
+		p.writeCreatedLog(path)
+	} else {
+		p.errors = append(p.errors, err)
+	}
+}
+
+func (p *basePublisher) writeCreatedLog(path string) {
+	if p.logger != nil {
+		fmt.Fprintln(p.logger, "Created:", path)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPublishTableNamedIndex(t *testing.T) {
+	tests := []struct {
+		format string
+		index  string
+		link   string
+		page   string
+		table  string
+	}{
+		{"html", "index.html", `<a href="tables/index.html">index</a>`, "tables/index.html", "<h1>index</h1>"},
+		{"json", "00_index.json", `"file": "index.json"`, "index.json", `"name": "index"`},
+		{"asciidoc", "index.adoc", "include::tables/index.adoc[]\n", "tables/index.adoc", "[[index]]\n== index\n"},
+		{"rst", "index.rst", "\n   tables/index\n", "tables/index.rst", ".. _table-index:\n\nindex\n"},
+		{"confluence", "index.xml", `<ri:page ri:content-title="index" />`, "tables/index.xml", "<td>index_pkey</td>"},
+		{"textile", "index.textile", "|[[index]] | |\n", "tables/index.textile", "h1. index\n"},
+	}
+	for _, tt := range tests {
+		tables := tablesFromDDL(`CREATE TABLE "index" (id serial PRIMARY KEY);`)
+		out := newMemoryOutput()
+		p, err := findPublisher(tt.format, &Config{Driver: "postgres"}, findConverter(true, "postgres"), l("en"), out, &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Publish(tables)
+		if errs := p.Errors(); len(errs) > 0 {
+			t.Fatalf("%s: %v", tt.format, errs)
+		}
+		if idx := string(out.files[tt.index]); !strings.Contains(idx, tt.link) {
+			t.Errorf("%s: %s should contain %s, actual:\n%s", tt.format, tt.index, tt.link, idx)
+		}
+		if page := string(out.files[tt.page]); !strings.Contains(page, tt.table) {
+			t.Errorf("%s: %s should be page of table, actual:\n%s", tt.format, tt.page, page)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pinzolo/dbmodel"
+)
+
+var (
+	rstInlinePattern     = regexp.MustCompile("([\\\\*`|])")
+	rstUnderscorePattern = regexp.MustCompile(`_(\W|$)`)
+)
+
+// rstPublisher writes reStructuredText pages that can be put in Sphinx project.
+type rstPublisher struct {
+	basePublisher
+}
+
+func newRSTPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *rstPublisher {
+	return &rstPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *rstPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	labels := make(map[string]string)
+	for _, tbl := range tables {
+		labels[ddlKey(tbl.Schema(), tbl.Name())] = rstLabel(tbl.Name())
+	}
+	for _, tbl := range tables {
+		p.write(rstPage(tbl.Name())+".rst", convertToRST(tbl, labels, p.conv, p.loc))
+	}
+	p.write("index.rst", convertToIndexRST(tables, p.loc))
+}
+
+// convertToRST makes same sections as convertToMarkdown with list-table directives.
+// Tables of foreign keys and referenced keys are linked by :ref: when they are in labels.
+func convertToRST(table *dbmodel.Table, labels map[string]string, conv Converter, loc locale) []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, ".. _%s:\n\n", rstLabel(table.Name()))
+	writeRSTTitle(buf, table.Name(), "=")
+	if table.Comment() != "" {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, rstEscape(table.Comment()))
+	}
+
+	rows := make([][]string, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		rows = append(rows, rstEscapeRow(conv.ConvertColumn(col)))
+	}
+	writeRSTSection(buf, loc.t("column", "title"), translateHeaders(loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"), rows)
+
+	if len(table.Indices()) > 0 {
+		rows = make([][]string, 0, len(table.Indices()))
+		for _, idx := range table.Indices() {
+			rows = append(rows, rstEscapeRow(conv.ConvertIndex(idx)))
+		}
+		writeRSTSection(buf, loc.t("index", "title"), translateHeaders(loc, "index", "name", "columns", "unique"), rows)
+	}
+
+	if len(table.Constraints()) > 0 {
+		rows = make([][]string, 0, len(table.Constraints()))
+		for _, con := range table.Constraints() {
+			rows = append(rows, rstEscapeRow(conv.ConvertConstraint(con)))
+		}
+		writeRSTSection(buf, loc.t("constraint", "title"), translateHeaders(loc, "constraint", "name", "kind", "content"), rows)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ForeignKeys()))
+		for _, fk := range table.ForeignKeys() {
+			values := conv.ConvertForeignKey(fk)
+			row := rstEscapeRow(values)
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				if label, ok := labels[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]; ok {
+					row[2] = rstRef(values[2], label)
+				}
+			}
+			rows = append(rows, row)
+		}
+		writeRSTSection(buf, loc.t("foreign_key", "title"), translateHeaders(loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"), rows)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ReferencedKeys()))
+		for _, rk := range table.ReferencedKeys() {
+			values := conv.ConvertReferencedKey(rk)
+			row := rstEscapeRow(values)
+			if label, ok := labels[ddlKey(rk.Schema(), rk.TableName())]; ok {
+				row[1] = rstRef(values[1], label)
+			}
+			rows = append(rows, row)
+		}
+		writeRSTSection(buf, loc.t("referenced_key", "title"), translateHeaders(loc, "referenced_key", "name", "source_table", "source_columns", "columns"), rows)
+	}
+
+	return buf.Bytes()
+}
+
+// convertToIndexRST makes index page that has table list and toctree of all tables.
+func convertToIndexRST(tables []*dbmodel.Table, loc locale) []byte {
+	buf := &bytes.Buffer{}
+
+	writeRSTTitle(buf, loc.t("table_list", "title"), "=")
+	fmt.Fprintln(buf)
+	rows := make([][]string, 0, len(tables))
+	for _, tbl := range tables {
+		rows = append(rows, []string{rstRef(tbl.Name(), rstLabel(tbl.Name())), rstEscape(tbl.Comment())})
+	}
+	writeRSTListTable(buf, translateHeaders(loc, "table_list", "table", "comment"), rows)
+
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, ".. toctree::")
+	fmt.Fprintln(buf, "   :maxdepth: 1")
+	fmt.Fprintln(buf)
+	for _, tbl := range tables {
+		fmt.Fprintln(buf, "   "+rstPage(tbl.Name()))
+	}
+	return buf.Bytes()
+}
+
+// writeRSTTitle writes title with underline that is as wide as title.
+func writeRSTTitle(buf *bytes.Buffer, title string, mark string) {
+	fmt.Fprintln(buf, title)
+	fmt.Fprintln(buf, strings.Repeat(mark, runewidth.StringWidth(title)))
+}
+
+func writeRSTSection(buf *bytes.Buffer, title string, headers []string, rows [][]string) {
+	fmt.Fprintln(buf)
+	writeRSTTitle(buf, title, "-")
+	fmt.Fprintln(buf)
+	writeRSTListTable(buf, rstEscapeRow(headers), rows)
+}
+
+// writeRSTListTable writes list-table directive. Values of rows must be escaped.
+func writeRSTListTable(buf *bytes.Buffer, headers []string, rows [][]string) {
+	fmt.Fprintln(buf, ".. list-table::")
+	fmt.Fprintln(buf, "   :header-rows: 1")
+	fmt.Fprintln(buf)
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, v := range row {
+			mark := "     -"
+			if i == 0 {
+				mark = "   * -"
+			}
+			fmt.Fprintln(buf, rstCell(mark, v))
+		}
+	}
+}
+
+// rstCell returns item of list. Multi-line value is written as line block to keep line breaks.
+func rstCell(mark string, v string) string {
+	if v == "" {
+		return mark
+	}
+	lines := strings.Split(v, "\n")
+	if len(lines) == 1 {
+		return mark + " " + v
+	}
+	return mark + " | " + strings.Join(lines, "\n       | ")
+}
+
+func rstRef(text string, label string) string {
+	return fmt.Sprintf(":ref:`%s <%s>`", strings.NewReplacer("`", "\\`", "<", "\\<").Replace(text), label)
+}
+
+// rstPage returns document name of table page.
+// Pages are put in tables directory, because index.rst is master document of Sphinx and table may be named index.
+func rstPage(name string) string {
+	return "tables/" + name
+}
+
+// rstLabel returns label of table page. Label is prefixed because labels are shared in Sphinx project.
+func rstLabel(name string) string {
+	return "table-" + strings.ToLower(diagramAlias(name))
+}
+
+func rstEscapeRow(values []string) []string {
+	escaped := make([]string, 0, len(values))
+	for _, v := range values {
+		escaped = append(escaped, rstEscape(v))
+	}
+	return escaped
+}
+
+// rstEscape escapes characters of inline markup, and underscore at end of word that makes reference.
+func rstEscape(s string) string {
+	s = rstInlinePattern.ReplaceAllString(s, `\$1`)
+	return rstUnderscorePattern.ReplaceAllString(s, `\_$1`)
+}
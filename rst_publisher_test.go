@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvertToRST(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id serial PRIMARY KEY, name varchar(50) NOT NULL DEFAULT '*none*');
+COMMENT ON TABLE shops IS 'Shops';
+COMMENT ON COLUMN shops.name IS 'Name
+of shop_';
+CREATE TABLE staffs (id serial PRIMARY KEY, shop_id int NOT NULL REFERENCES shops (id));
+`)
+	labels := map[string]string{ddlKey("public", "shops"): "table-shops"}
+	var shops, staffs = tables[0], tables[1]
+	if shops.Name() != "shops" {
+		shops, staffs = staffs, shops
+	}
+	conv := findConverter(true, "postgres")
+
+	expected := `.. _table-shops:
+
+shops
+=====
+
+Shops
+
+Columns
+-------
+
+.. list-table::
+   :header-rows: 1
+
+   * - PK
+     - NAME
+     - TYPE
+     - SIZE
+     - NULL
+     - DEFAULT
+     - COMMENT
+   * - 1
+     - id
+     - serial
+     -
+     - NO
+     -
+     -
+   * -
+     - name
+     - varchar
+     - 50
+     - NO
+     - '\*none\*'
+     - | Name
+       | of shop\_
+
+Indices
+-------
+
+.. list-table::
+   :header-rows: 1
+
+   * - NAME
+     - COLUMNS
+     - UNIQUE
+   * - shops_pkey
+     - id
+     - YES
+
+Referenced keys
+---------------
+
+.. list-table::
+   :header-rows: 1
+
+   * - NAME
+     - SOURCE TABLE
+     - SOURCE COLUMNS
+     - COLUMNS
+   * - staffs_shop_id_fkey
+     - staffs
+     - shop_id
+     - id
+`
+	if actual := string(convertToRST(shops, labels, conv, l("en"))); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+
+	actual := string(convertToRST(staffs, labels, conv, l("ja")))
+	for _, s := range []string{
+		".. _table-staffs:\n\nstaffs\n======\n",
+		"\n列一覧\n" + "------\n",
+		"   * - staffs_shop_id_fkey\n     - shop_id\n     - :ref:`shops <table-shops>`\n     - id\n",
+	} {
+		if !strings.Contains(actual, s) {
+			t.Errorf("staffs.rst should contain %s, actual:\n%s", s, actual)
+		}
+	}
+}
+
+func TestRSTEscape(t *testing.T) {
+	tests := map[string]string{
+		"user_id":     "user_id",
+		"name_":       `name\_`,
+		"a_ b":        `a\_ b`,
+		"`x` | *y*":   "\\`x\\` \\| \\*y\\*",
+		`C:\path`:     `C:\\path`,
+		"'a'::text":   "'a'::text",
+		"foo__ (bar)": `foo_\_ (bar)`,
+	}
+	for s, expected := range tests {
+		if actual := rstEscape(s); expected != actual {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestCmdPublishRST(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "rst"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/tables/posts.rst
+Created: out/tables/users.rst
+Created: out/index.rst
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = "Table index\n===========\n\n" +
+		".. list-table::\n   :header-rows: 1\n\n" +
+		"   * - TABLE\n     - COMMENT\n" +
+		"   * - :ref:`posts <table-posts>`\n     - Posts of users\n" +
+		"   * - :ref:`users <table-users>`\n     -\n\n" +
+		".. toctree::\n   :maxdepth: 1\n\n   tables/posts\n   tables/users\n"
+	if actual := readPublished(t, dir, "index.rst"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	if users := readPublished(t, dir, "tables/users.rst"); !strings.Contains(users, "   * - posts_user_id_fkey\n     - :ref:`posts <table-posts>`\n") {
+		t.Errorf("tables/users.rst should have reference to posts, actual:\n%s", users)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+var (
+	columnDiffAttributes     = []string{"primary_key", "name", "type", "size", "null", "default", "comment"}
+	indexDiffAttributes      = []string{"name", "columns", "unique"}
+	constraintDiffAttributes = []string{"name", "kind", "content"}
+	foreignKeyDiffAttributes = []string{"name", "columns", "foreign_table", "foreign_columns"}
+)
+
+// schemaDiff is differences between tables of two schemas.
+type schemaDiff struct {
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Tables []*tableDiff `json:"tables"`
+}
+
+// tableDiff is differences of a table. Elements of added or removed table are not listed.
+type tableDiff struct {
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	Changes     []*valueChange `json:"changes,omitempty"`
+	Columns     []*elementDiff `json:"columns,omitempty"`
+	Indices     []*elementDiff `json:"indices,omitempty"`
+	Constraints []*elementDiff `json:"constraints,omitempty"`
+	ForeignKeys []*elementDiff `json:"foreign_keys,omitempty"`
+
+	from *dbmodel.Table
+	to   *dbmodel.Table
+}
+
+// elementDiff is difference of column, index, constraint or foreign key.
+type elementDiff struct {
+	Name    string         `json:"name"`
+	Status  string         `json:"status"`
+	Changes []*valueChange `json:"changes,omitempty"`
+
+	from interface{}
+	to   interface{}
+}
+
+type valueChange struct {
+	Attribute string `json:"attribute"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+func (d *schemaDiff) hasDifferences() bool {
+	return len(d.Tables) > 0
+}
+
+// diffSchemas compares tables by name. Values are compared as converter prints them.
+func diffSchemas(from []*dbmodel.Table, to []*dbmodel.Table, conv Converter) *schemaDiff {
+	fromMap := make(map[string]*dbmodel.Table)
+	toMap := make(map[string]*dbmodel.Table)
+	names := make([]string, 0)
+	for _, tbl := range from {
+		fromMap[tbl.Name()] = tbl
+		names = append(names, tbl.Name())
+	}
+	for _, tbl := range to {
+		toMap[tbl.Name()] = tbl
+		if _, ok := fromMap[tbl.Name()]; !ok {
+			names = append(names, tbl.Name())
+		}
+	}
+	sort.Strings(names)
+
+	d := &schemaDiff{Tables: make([]*tableDiff, 0)}
+	for _, name := range names {
+		ft, fok := fromMap[name]
+		tt, tok := toMap[name]
+		switch {
+		case !fok:
+			d.Tables = append(d.Tables, &tableDiff{Name: name, Status: diffAdded, to: tt})
+		case !tok:
+			d.Tables = append(d.Tables, &tableDiff{Name: name, Status: diffRemoved, from: ft})
+		default:
+			if td := diffTable(ft, tt, conv); td != nil {
+				d.Tables = append(d.Tables, td)
+			}
+		}
+	}
+	return d
+}
+
+// diffTable returns differences of table, or nil when there is no difference.
+func diffTable(from *dbmodel.Table, to *dbmodel.Table, conv Converter) *tableDiff {
+	td := &tableDiff{Name: to.Name(), Status: diffChanged, from: from, to: to}
+	if from.Comment() != to.Comment() {
+		td.Changes = append(td.Changes, &valueChange{Attribute: "comment", From: from.Comment(), To: to.Comment()})
+	}
+
+	fromCols := make([]diffElement, 0, len(from.Columns()))
+	for _, col := range from.Columns() {
+		fromCols = append(fromCols, diffElement{col.Name(), col, conv.ConvertColumn(col)})
+	}
+	toCols := make([]diffElement, 0, len(to.Columns()))
+	for _, col := range to.Columns() {
+		toCols = append(toCols, diffElement{col.Name(), col, conv.ConvertColumn(col)})
+	}
+	td.Columns = diffElements(fromCols, toCols, columnDiffAttributes, false)
+
+	fromIdxs := make([]diffElement, 0, len(from.Indices()))
+	for _, idx := range from.Indices() {
+		fromIdxs = append(fromIdxs, diffElement{idx.Name(), idx, conv.ConvertIndex(idx)})
+	}
+	toIdxs := make([]diffElement, 0, len(to.Indices()))
+	for _, idx := range to.Indices() {
+		toIdxs = append(toIdxs, diffElement{idx.Name(), idx, conv.ConvertIndex(idx)})
+	}
+	td.Indices = diffElements(fromIdxs, toIdxs, indexDiffAttributes, true)
+
+	fromCons := make([]diffElement, 0, len(from.Constraints()))
+	for _, con := range from.Constraints() {
+		fromCons = append(fromCons, diffElement{con.Name(), con, conv.ConvertConstraint(con)})
+	}
+	toCons := make([]diffElement, 0, len(to.Constraints()))
+	for _, con := range to.Constraints() {
+		toCons = append(toCons, diffElement{con.Name(), con, conv.ConvertConstraint(con)})
+	}
+	td.Constraints = diffElements(fromCons, toCons, constraintDiffAttributes, true)
+
+	fromFks := make([]diffElement, 0, len(from.ForeignKeys()))
+	for _, fk := range from.ForeignKeys() {
+		fromFks = append(fromFks, diffElement{fk.Name(), fk, conv.ConvertForeignKey(fk)})
+	}
+	toFks := make([]diffElement, 0, len(to.ForeignKeys()))
+	for _, fk := range to.ForeignKeys() {
+		toFks = append(toFks, diffElement{fk.Name(), fk, conv.ConvertForeignKey(fk)})
+	}
+	td.ForeignKeys = diffElements(fromFks, toFks, foreignKeyDiffAttributes, true)
+
+	if len(td.Changes) == 0 && len(td.Columns) == 0 && len(td.Indices) == 0 && len(td.Constraints) == 0 && len(td.ForeignKeys) == 0 {
+		return nil
+	}
+	return td
+}
+
+// diffElement is an element to compare with its converted values.
+type diffElement struct {
+	name   string
+	model  interface{}
+	values []string
+}
+
+// diffElements compares elements by name.
+// Result follows order of 'to' elements and removed elements are appended, or is sorted by name when sorted is true.
+func diffElements(from []diffElement, to []diffElement, attrs []string, sorted bool) []*elementDiff {
+	fromMap := make(map[string]diffElement)
+	for _, e := range from {
+		fromMap[e.name] = e
+	}
+	toMap := make(map[string]diffElement)
+	for _, e := range to {
+		toMap[e.name] = e
+	}
+
+	diffs := make([]*elementDiff, 0)
+	for _, te := range to {
+		fe, ok := fromMap[te.name]
+		if !ok {
+			diffs = append(diffs, &elementDiff{Name: te.name, Status: diffAdded, to: te.model})
+			continue
+		}
+		changes := make([]*valueChange, 0)
+		for i, attr := range attrs {
+			if i < len(fe.values) && i < len(te.values) && fe.values[i] != te.values[i] {
+				changes = append(changes, &valueChange{Attribute: attr, From: fe.values[i], To: te.values[i]})
+			}
+		}
+		if len(changes) > 0 {
+			diffs = append(diffs, &elementDiff{Name: te.name, Status: diffChanged, Changes: changes, from: fe.model, to: te.model})
+		}
+	}
+	for _, fe := range from {
+		if _, ok := toMap[fe.name]; !ok {
+			diffs = append(diffs, &elementDiff{Name: fe.name, Status: diffRemoved, from: fe.model})
+		}
+	}
+	if sorted {
+		sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	}
+	return diffs
+}
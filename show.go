@@ -24,13 +24,31 @@ Options:
         use config file instead of default config file(.tablarian.config)
         if CONFIG_FILE starts with '@', it is treated as absolute file path.
 
+    --ddl DDL_FILE
+        load table definitions from DDL_FILE instead of connecting to database.
+        driver in config file is used as SQL dialect.
+        unsupported statements are skipped with warning.
+        if DDL_FILE starts with '@', it is treated as absolute file path.
+
+    --migrations MIGRATIONS_DIR
+        load table definitions by applying up-migrations in MIGRATIONS_DIR in order of version.
+        Flyway(V1__name.sql), golang-migrate(1_name.up.sql) and goose(1_name.sql) file names are acceptable.
+        if MIGRATIONS_DIR starts with '@', it is treated as absolute file path.
+
+    --until VERSION
+        apply migrations until VERSION. (use with --migrations)
+
+    --snapshot SNAPSHOT_FILE
+        load table definitions from SNAPSHOT_FILE made by dump command instead of connecting to database.
+        if SNAPSHOT_FILE starts with '@', it is treated as absolute file path.
+
     -a, --all
         show all metadata of table.(indices, foreign keys, referenced keys, constraints)
         without this option, print only column definitions.
 
     -p, --pretty
         convert data type to usually name.
-        this option is author's personal option. (only PostgreSQL)
+        this option is author's personal option. (PostgreSQL and MySQL)
 	`,
 	}
 	showOpt = showOption{}
@@ -39,6 +57,10 @@ Options:
 func init() {
 	cmdShow.Flag.StringVar(&showOpt.configFile, "config", DefaultConfigFileName, "Config file path")
 	cmdShow.Flag.StringVar(&showOpt.configFile, "c", DefaultConfigFileName, "Config file path")
+	cmdShow.Flag.StringVar(&showOpt.ddlFile, "ddl", "", "DDL file path")
+	cmdShow.Flag.StringVar(&showOpt.migrationsDir, "migrations", "", "Migrations directory path")
+	cmdShow.Flag.StringVar(&showOpt.until, "until", "", "Migration version")
+	cmdShow.Flag.StringVar(&showOpt.snapshotFile, "snapshot", "", "Snapshot file path")
 	cmdShow.Flag.BoolVar(&showOpt.showAll, "all", false, "Show all metadata of table")
 	cmdShow.Flag.BoolVar(&showOpt.showAll, "a", false, "Show all metadata of table")
 	cmdShow.Flag.BoolVar(&showOpt.prettyPrint, "pretty", false, "Pretty print")
@@ -52,19 +74,18 @@ func runShow(args []string) int {
 		fmt.Fprintln(o.err, err)
 		return 1
 	}
-	db := dbClientFor(cfg)
-	db.Connect()
-	defer db.Disconnect()
-
-	opt := dbmodel.RequireNone
-	if showOpt.showAll {
-		opt = dbmodel.RequireAll
+	src, err := findSource(cfg, showOpt.baseOption)
+	if err != nil {
+		fmt.Fprintln(o.err, err)
+		return 1
 	}
+	defer src.Close()
+
 	if len(args) == 0 {
 		fmt.Fprintln(o.err, "require table name as argument.")
 		return 1
 	}
-	tbl, err := db.Table(cfg.Schema, args[0], opt)
+	tbl, err := src.Table(cfg.Schema, args[0], showOpt.showAll)
 	if err != nil {
 		fmt.Fprintln(o.err, err)
 		return 1
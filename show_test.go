@@ -210,4 +210,8 @@ func initShowOpt() {
 	showOpt.configFile = DefaultConfigFileName
 	showOpt.showAll = false
 	showOpt.prettyPrint = false
+	showOpt.ddlFile = ""
+	showOpt.migrationsDir = ""
+	showOpt.until = ""
+	showOpt.snapshotFile = ""
 }
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// snapshotFormatVersion is version of snapshot file format.
+// Increment it when format is changed incompatibly.
+const snapshotFormatVersion = 1
+
+// snapshot is serializable form of all tables in schema.
+// Tables and their elements are sorted by name, and neither timestamp nor version of tablarian is contained,
+// so same schema makes same file.
+type snapshot struct {
+	FormatVersion int              `json:"format_version"`
+	Driver        string           `json:"driver"`
+	Schema        string           `json:"schema"`
+	Tables        []*snapshotTable `json:"tables"`
+}
+
+type snapshotTable struct {
+	Schema         string                `json:"schema"`
+	Name           string                `json:"name"`
+	Comment        string                `json:"comment"`
+	Columns        []*snapshotColumn     `json:"columns"`
+	Indices        []*snapshotIndex      `json:"indices"`
+	Constraints    []*snapshotConstraint `json:"constraints"`
+	ForeignKeys    []*snapshotForeignKey `json:"foreign_keys"`
+	ReferencedKeys []*snapshotForeignKey `json:"referenced_keys"`
+}
+
+type snapshotColumn struct {
+	Name               string `json:"name"`
+	Comment            string `json:"comment"`
+	DataType           string `json:"data_type"`
+	Length             *int64 `json:"length"`
+	Precision          *int64 `json:"precision"`
+	Scale              *int64 `json:"scale"`
+	Nullable           bool   `json:"nullable"`
+	Default            string `json:"default"`
+	PrimaryKeyPosition int64  `json:"primary_key_position"`
+}
+
+type snapshotIndex struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+type snapshotConstraint struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Content string `json:"content"`
+}
+
+type snapshotForeignKey struct {
+	Name           string   `json:"name"`
+	Schema         string   `json:"schema"`
+	Table          string   `json:"table"`
+	Columns        []string `json:"columns"`
+	ForeignSchema  string   `json:"foreign_schema"`
+	ForeignTable   string   `json:"foreign_table"`
+	ForeignColumns []string `json:"foreign_columns"`
+}
+
+func newSnapshot(config *Config, tables []*dbmodel.Table) *snapshot {
+	snap := &snapshot{
+		FormatVersion: snapshotFormatVersion,
+		Driver:        config.Driver,
+		Schema:        config.Schema,
+		Tables:        make([]*snapshotTable, 0, len(tables)),
+	}
+	for _, tbl := range tables {
+		snap.Tables = append(snap.Tables, newSnapshotTable(tbl))
+	}
+	sort.Slice(snap.Tables, func(i, j int) bool {
+		if snap.Tables[i].Schema != snap.Tables[j].Schema {
+			return snap.Tables[i].Schema < snap.Tables[j].Schema
+		}
+		return snap.Tables[i].Name < snap.Tables[j].Name
+	})
+	return snap
+}
+
+func newSnapshotTable(tbl *dbmodel.Table) *snapshotTable {
+	st := &snapshotTable{
+		Schema:         tbl.Schema(),
+		Name:           tbl.Name(),
+		Comment:        tbl.Comment(),
+		Columns:        make([]*snapshotColumn, 0, len(tbl.Columns())),
+		Indices:        make([]*snapshotIndex, 0, len(tbl.Indices())),
+		Constraints:    make([]*snapshotConstraint, 0, len(tbl.Constraints())),
+		ForeignKeys:    make([]*snapshotForeignKey, 0, len(tbl.ForeignKeys())),
+		ReferencedKeys: make([]*snapshotForeignKey, 0, len(tbl.ReferencedKeys())),
+	}
+	// columns keep ordinal position.
+	for _, col := range tbl.Columns() {
+		size := col.Size()
+		st.Columns = append(st.Columns, &snapshotColumn{
+			Name:               col.Name(),
+			Comment:            col.Comment(),
+			DataType:           col.DataType(),
+			Length:             nullInt64Ptr(size.Length()),
+			Precision:          nullInt64Ptr(size.Precision()),
+			Scale:              nullInt64Ptr(size.Scale()),
+			Nullable:           col.IsNullable(),
+			Default:            col.DefaultValue(),
+			PrimaryKeyPosition: col.PrimaryKeyPosition(),
+		})
+	}
+	for _, idx := range tbl.Indices() {
+		st.Indices = append(st.Indices, &snapshotIndex{
+			Name:    idx.Name(),
+			Columns: columnNames(idx.Columns()),
+			Unique:  idx.IsUnique(),
+		})
+	}
+	sort.Slice(st.Indices, func(i, j int) bool { return st.Indices[i].Name < st.Indices[j].Name })
+	for _, con := range tbl.Constraints() {
+		st.Constraints = append(st.Constraints, &snapshotConstraint{
+			Name:    con.Name(),
+			Kind:    con.Kind(),
+			Content: con.Content(),
+		})
+	}
+	sort.Slice(st.Constraints, func(i, j int) bool { return st.Constraints[i].Name < st.Constraints[j].Name })
+	for _, fk := range tbl.ForeignKeys() {
+		st.ForeignKeys = append(st.ForeignKeys, newSnapshotForeignKey(fk))
+	}
+	sortSnapshotForeignKeys(st.ForeignKeys)
+	for _, rk := range tbl.ReferencedKeys() {
+		st.ReferencedKeys = append(st.ReferencedKeys, newSnapshotForeignKey(rk))
+	}
+	sortSnapshotForeignKeys(st.ReferencedKeys)
+	return st
+}
+
+func newSnapshotForeignKey(fk *dbmodel.ForeignKey) *snapshotForeignKey {
+	sfk := &snapshotForeignKey{
+		Name:           fk.Name(),
+		Schema:         fk.Schema(),
+		Table:          fk.TableName(),
+		Columns:        make([]string, 0, len(fk.ColumnReferences())),
+		ForeignColumns: make([]string, 0, len(fk.ColumnReferences())),
+	}
+	for _, ref := range fk.ColumnReferences() {
+		sfk.Columns = append(sfk.Columns, ref.From().Name())
+		sfk.ForeignSchema = ref.To().Schema()
+		sfk.ForeignTable = ref.To().TableName()
+		sfk.ForeignColumns = append(sfk.ForeignColumns, ref.To().Name())
+	}
+	return sfk
+}
+
+func sortSnapshotForeignKeys(fks []*snapshotForeignKey) {
+	sort.Slice(fks, func(i, j int) bool {
+		if fks[i].Name != fks[j].Name {
+			return fks[i].Name < fks[j].Name
+		}
+		return ddlKey(fks[i].Schema, fks[i].Table) < ddlKey(fks[j].Schema, fks[j].Table)
+	})
+}
+
+func columnNames(cols []*dbmodel.Column) []string {
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, col.Name())
+	}
+	return names
+}
+
+func nullInt64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int64
+	return &v
+}
+
+func ptrNullInt64(p *int64) sql.NullInt64 {
+	if p == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *p, Valid: true}
+}
+
+// marshal returns indented JSON that ends with new line.
+func (snap *snapshot) marshal() ([]byte, error) {
+	b, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func loadSnapshot(path string) (*snapshot, error) {
+	rpath, err := resolvePath(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(rpath)
+	if err != nil {
+		return nil, err
+	}
+	snap := &snapshot{}
+	if err = json.Unmarshal(content, snap); err != nil {
+		return nil, err
+	}
+	if snap.FormatVersion < 1 || snap.FormatVersion > snapshotFormatVersion {
+		return nil, fmt.Errorf("Snapshot format version '%d' is not supported.", snap.FormatVersion)
+	}
+	return snap, nil
+}
+
+// build makes dbmodel tables from snapshot.
+func (snap *snapshot) build() []*dbmodel.Table {
+	tables := make([]*dbmodel.Table, 0, len(snap.Tables))
+	tblMap := make(map[string]*dbmodel.Table)
+	colMap := make(map[string]*dbmodel.Column)
+	for _, st := range snap.Tables {
+		tbl := dbmodel.NewTable(st.Schema, st.Name, st.Comment)
+		for _, sc := range st.Columns {
+			size := dbmodel.NewSize(ptrNullInt64(sc.Length), ptrNullInt64(sc.Precision), ptrNullInt64(sc.Scale))
+			col := dbmodel.NewColumn(st.Schema, st.Name, sc.Name, sc.Comment, sc.DataType, size, sc.Nullable, sc.Default, sc.PrimaryKeyPosition)
+			tbl.AddColumn(&col)
+			colMap[columnKey(st.Schema, st.Name, sc.Name)] = &col
+		}
+		for _, si := range st.Indices {
+			idx := dbmodel.NewIndex(st.Schema, st.Name, si.Name, si.Unique)
+			for _, c := range si.Columns {
+				idx.AddColumn(findOrStubColumn(colMap, st.Schema, st.Name, c))
+			}
+			tbl.AddIndex(&idx)
+		}
+		for _, sc := range st.Constraints {
+			con := dbmodel.NewConstraint(st.Schema, st.Name, sc.Name, sc.Kind, sc.Content)
+			tbl.AddConstraint(&con)
+		}
+		tables = append(tables, &tbl)
+		tblMap[ddlKey(st.Schema, st.Name)] = &tbl
+	}
+
+	// foreign key is shared by referencing table and referenced table like database client does.
+	fkMap := make(map[string]*dbmodel.ForeignKey)
+	for _, st := range snap.Tables {
+		tbl := tblMap[ddlKey(st.Schema, st.Name)]
+		for _, sfk := range st.ForeignKeys {
+			fk := sfk.build(colMap)
+			tbl.AddForeignKey(fk)
+			fkMap[ddlKey(ddlKey(sfk.Schema, sfk.Table), sfk.Name)] = fk
+		}
+	}
+	for _, st := range snap.Tables {
+		tbl := tblMap[ddlKey(st.Schema, st.Name)]
+		for _, srk := range st.ReferencedKeys {
+			if fk, ok := fkMap[ddlKey(ddlKey(srk.Schema, srk.Table), srk.Name)]; ok {
+				tbl.AddReferencedKey(fk)
+			} else {
+				tbl.AddReferencedKey(srk.build(colMap))
+			}
+		}
+	}
+	return tables
+}
+
+func (sfk *snapshotForeignKey) build(colMap map[string]*dbmodel.Column) *dbmodel.ForeignKey {
+	fk := dbmodel.NewForeignKey(sfk.Schema, sfk.Table, sfk.Name)
+	for i, c := range sfk.Columns {
+		if i >= len(sfk.ForeignColumns) {
+			break
+		}
+		ref := dbmodel.NewColumnReference(findOrStubColumn(colMap, sfk.Schema, sfk.Table, c), findOrStubColumn(colMap, sfk.ForeignSchema, sfk.ForeignTable, sfk.ForeignColumns[i]))
+		fk.AddColumnReference(&ref)
+	}
+	return &fk
+}
+
+// snapshotSource loads table definitions from snapshot file made by dump command.
+type snapshotSource struct {
+	snap   *snapshot
+	tables []*dbmodel.Table
+}
+
+func newSnapshotSource(path string) (*snapshotSource, error) {
+	snap, err := loadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotSource{snap: snap, tables: snap.build()}, nil
+}
+
+func (s *snapshotSource) AllTableNames(schema string) ([]*dbmodel.Table, error) {
+	return s.AllTables(schema)
+}
+
+// AllTables returns all tables in snapshot. Schema is ignored, because snapshot is dumped for a schema.
+func (s *snapshotSource) AllTables(schema string) ([]*dbmodel.Table, error) {
+	return s.tables, nil
+}
+
+func (s *snapshotSource) Table(schema string, name string, all bool) (*dbmodel.Table, error) {
+	for _, tbl := range s.tables {
+		if tbl.Name() != name {
+			continue
+		}
+		if all {
+			return tbl, nil
+		}
+		return columnsOnly(tbl), nil
+	}
+	return nil, fmt.Errorf("Table '%s' is not found.", name)
+}
+
+func (s *snapshotSource) Close() {
+}
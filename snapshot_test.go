@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSnapshotWithUnsupportedVersion(t *testing.T) {
+	dir := filepath.Join("test", "tmp")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir, 0755)
+	path := filepath.Join(dir, "snapshot.json")
+	writeToFile(path, []byte(`{"format_version": 99, "tables": []}`))
+
+	_, err := loadSnapshot(path)
+	if err == nil {
+		t.Fatal("Unsupported format version should be error.")
+	}
+	if expected, actual := "Snapshot format version '99' is not supported.", err.Error(); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestSnapshotDoesNotDependOnTablarianVersion(t *testing.T) {
+	tables := tablesFromDDL("CREATE TABLE users (id integer PRIMARY KEY);")
+	before, _ := newSnapshot(&Config{Driver: "postgres"}, tables).marshal()
+	defer func(v string) { Version = v }(Version)
+	Version = "99.0.0"
+	after, _ := newSnapshot(&Config{Driver: "postgres"}, tables).marshal()
+	if !bytes.Equal(before, after) {
+		t.Errorf("Snapshot should be same after upgrade.\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+}
+
+func TestLoadSnapshotWithTablarianVersion(t *testing.T) {
+	dir := filepath.Join("test", "tmp")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir, 0755)
+	path := filepath.Join(dir, "snapshot.json")
+	writeToFile(path, []byte(`{"format_version": 1, "tablarian_version": "0.5.0", "driver": "postgres", "tables": []}`))
+
+	if _, err := loadSnapshot(path); err != nil {
+		t.Errorf("Snapshot that is written by older version should be loaded. error: %v", err)
+	}
+}
+
+func TestFindSourceWithSnapshotAndDDL(t *testing.T) {
+	_, err := findSource(&Config{Driver: "postgres"}, baseOption{ddlFile: "a.sql", snapshotFile: "a.json"})
+	if err == nil {
+		t.Error("--ddl with --snapshot should be error.")
+	}
+}
+
+func TestCmdIndexWithSnapshot(t *testing.T) {
+	dir := filepath.Join("test", "tmp")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir, 0755)
+	path := filepath.Join(dir, "snapshot.json")
+	writeToFile(path, []byte(`{
+  "format_version": 1,
+  "driver": "postgres",
+  "schema": "public",
+  "tables": [
+    {"schema": "public", "name": "posts", "comment": "Posts", "columns": [{"name": "id", "data_type": "int4"}]},
+    {"schema": "public", "name": "users", "comment": "", "columns": []}
+  ]
+}`))
+
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	idxOpt.withoutTableComment = false
+	idxOpt.snapshotFile = path
+	defer func() { idxOpt.snapshotFile = "" }()
+	if stat := cmdIndex.Run([]string{}); stat != 0 {
+		t.Error("Index subcommand should finish normally.")
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	if expected, actual := "posts  Posts\nusers", strings.Join(lines, "\n"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
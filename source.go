@@ -0,0 +1,108 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// Source is interface for loading table definitions.
+type Source interface {
+	AllTableNames(schema string) ([]*dbmodel.Table, error)
+	AllTables(schema string) ([]*dbmodel.Table, error)
+	Table(schema string, name string, all bool) (*dbmodel.Table, error)
+	Close()
+}
+
+// findSource returns source for config.
+// When DDL file, migrations directory or snapshot file is given, tables are loaded from it instead of database.
+func findSource(config *Config, opt baseOption) (Source, error) {
+	n := 0
+	for _, v := range []string{opt.ddlFile, opt.migrationsDir, opt.snapshotFile} {
+		if v != "" {
+			n++
+		}
+	}
+	if n > 1 {
+		return nil, errors.New("--ddl, --migrations and --snapshot can not be used together.")
+	}
+	if opt.until != "" && opt.migrationsDir == "" {
+		return nil, errors.New("--until requires --migrations.")
+	}
+	if opt.ddlFile != "" {
+		return newDDLSource(config, opt.ddlFile, o.err)
+	}
+	if opt.migrationsDir != "" {
+		return newMigrationSource(config, opt.migrationsDir, opt.until, o.err)
+	}
+	if opt.snapshotFile != "" {
+		return newSnapshotSource(opt.snapshotFile)
+	}
+	if config.Driver == "mysql" {
+		return newMysqlSource(config)
+	}
+	if config.Driver == "sqlite3" {
+		return newSqliteSource(config)
+	}
+
+	return newDbmodelSource(config), nil
+}
+
+// dbmodelSource loads table definitions via dbmodel client.
+type dbmodelSource struct {
+	client *dbmodel.Client
+}
+
+func newDbmodelSource(config *Config) dbmodelSource {
+	client := dbClientFor(config)
+	client.Connect()
+	return dbmodelSource{client: client}
+}
+
+func (s dbmodelSource) AllTableNames(schema string) ([]*dbmodel.Table, error) {
+	return s.client.AllTableNames(schema)
+}
+
+func (s dbmodelSource) AllTables(schema string) ([]*dbmodel.Table, error) {
+	return s.client.AllTables(schema, dbmodel.RequireAll)
+}
+
+func (s dbmodelSource) Table(schema string, name string, all bool) (*dbmodel.Table, error) {
+	opt := dbmodel.RequireNone
+	if all {
+		opt = dbmodel.RequireAll
+	}
+	return s.client.Table(schema, name, opt)
+}
+
+func (s dbmodelSource) Close() {
+	s.client.Disconnect()
+}
+
+// columnsOnly returns copy of table that has only columns, like table loaded without dbmodel.RequireAll.
+func columnsOnly(tbl *dbmodel.Table) *dbmodel.Table {
+	t := dbmodel.NewTable(tbl.Schema(), tbl.Name(), tbl.Comment())
+	for _, col := range tbl.Columns() {
+		t.AddColumn(col)
+	}
+	return &t
+}
+
+// findOrStubColumn returns loaded column, or column that has only name when column is not loaded. (e.g. in other schema)
+func findOrStubColumn(colMap map[string]*dbmodel.Column, schema string, table string, name string) *dbmodel.Column {
+	if col, ok := colMap[columnKey(schema, table, name)]; ok {
+		return col
+	}
+	col := dbmodel.NewColumn(schema, table, name, "", "", emptySize(), true, "", 0)
+	return &col
+}
+
+func columnKey(schema string, table string, name string) string {
+	return schema + "." + table + "." + name
+}
+
+// emptySize returns size that has no value.
+func emptySize() dbmodel.Size {
+	return dbmodel.NewSize(sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuotedIdent
+	sqlString
+	sqlNumber
+	sqlSymbol
+)
+
+// sqlToken is lexical token of SQL text.
+// text is raw text in source, and pos is its byte offset.
+// unterminated is true when quoted text has no closing quote until end of source.
+type sqlToken struct {
+	kind         sqlTokenKind
+	text         string
+	pos          int
+	unterminated bool
+}
+
+// is returns true when token is given keyword. (case insensitive)
+func (t sqlToken) is(keyword string) bool {
+	return t.kind == sqlWord && strings.EqualFold(t.text, keyword)
+}
+
+// ident returns identifier name. Quoted identifier is unquoted.
+func (t sqlToken) ident() string {
+	if t.kind != sqlQuotedIdent {
+		return t.text
+	}
+	q := t.text[:1]
+	if q == "[" {
+		q = "]"
+	}
+	body := t.text[1:]
+	if !t.unterminated {
+		body = body[:len(body)-1]
+	}
+	return strings.Replace(body, q+q, q, -1)
+}
+
+// value returns content of string literal.
+func (t sqlToken) value() string {
+	if t.kind != sqlString {
+		return t.text
+	}
+	if strings.HasPrefix(t.text, "$") {
+		i := strings.Index(t.text[1:], "$") + 2
+		if t.unterminated {
+			return t.text[i:]
+		}
+		return t.text[i : len(t.text)-i]
+	}
+	body := t.text[strings.Index(t.text, "'")+1:]
+	if !t.unterminated {
+		body = body[:len(body)-1]
+	}
+	return strings.Replace(body, "''", "'", -1)
+}
+
+// lexSQL splits SQL text into tokens. Whitespaces and comments are skipped.
+func lexSQL(s string) []sqlToken {
+	tokens := make([]sqlToken, 0)
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(s[i:], "--"):
+			i = skipTo(s, i, "\n")
+		case strings.HasPrefix(s[i:], "/*"):
+			i = skipTo(s, i+2, "*/")
+		case c == '\'':
+			end, ok := quotedEnd(s, i, '\'')
+			tokens = append(tokens, sqlToken{kind: sqlString, text: s[i:end], pos: i, unterminated: !ok})
+			i = end
+		case (c == 'E' || c == 'e' || c == 'N' || c == 'n') && i+1 < len(s) && s[i+1] == '\'':
+			end, ok := quotedEnd(s, i+1, '\'')
+			tokens = append(tokens, sqlToken{kind: sqlString, text: s[i:end], pos: i, unterminated: !ok})
+			i = end
+		case c == '"' || c == '`':
+			end, ok := quotedEnd(s, i, c)
+			tokens = append(tokens, sqlToken{kind: sqlQuotedIdent, text: s[i:end], pos: i, unterminated: !ok})
+			i = end
+		case c == '[':
+			end, ok := quotedEnd(s, i, ']')
+			tokens = append(tokens, sqlToken{kind: sqlQuotedIdent, text: s[i:end], pos: i, unterminated: !ok})
+			i = end
+		case c == '$' && dollarTag(s[i:]) != "":
+			tag := dollarTag(s[i:])
+			end, ok := strings.Index(s[i+len(tag):], tag), true
+			if end < 0 {
+				end, ok = len(s), false
+			} else {
+				end = i + len(tag) + end + len(tag)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlString, text: s[i:end], pos: i, unterminated: !ok})
+			i = end
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			end := i + 1
+			for end < len(s) {
+				if isDigit(s[end]) || s[end] == '.' {
+					end++
+				} else if (s[end] == 'e' || s[end] == 'E') && end+1 < len(s) && isDigit(s[end+1]) {
+					end += 2
+				} else if (s[end] == 'e' || s[end] == 'E') && end+2 < len(s) && (s[end+1] == '-' || s[end+1] == '+') && isDigit(s[end+2]) {
+					end += 3
+				} else {
+					break
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: s[i:end], pos: i})
+			i = end
+		case isIdentStart(rune(c)) || c >= 0x80:
+			end := i
+			for end < len(s) {
+				r := rune(s[end])
+				if !(isIdentStart(r) || unicode.IsDigit(r) || r == '$' || s[end] >= 0x80) {
+					break
+				}
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlWord, text: s[i:end], pos: i})
+			i = end
+		default:
+			n := 1
+			for _, op := range []string{"::", "<=", ">=", "<>", "!=", "||", "->>", "->"} {
+				if strings.HasPrefix(s[i:], op) {
+					n = len(op)
+					break
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: sqlSymbol, text: s[i : i+n], pos: i})
+			i += n
+		}
+	}
+	return tokens
+}
+
+// splitSQLStatements splits tokens by semicolon.
+func splitSQLStatements(tokens []sqlToken) [][]sqlToken {
+	stmts := make([][]sqlToken, 0)
+	start := 0
+	for i, t := range tokens {
+		if t.kind == sqlSymbol && t.text == ";" {
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
+
+// matchParen returns index of close paren that matches open paren at i.
+// When close paren is not found, it returns last index.
+func matchParen(tokens []sqlToken, i int) int {
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		if tokens[j].kind != sqlSymbol {
+			continue
+		}
+		switch tokens[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// sourceText returns raw text from first token to last token in source.
+func sourceText(s string, tokens []sqlToken) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	last := tokens[len(tokens)-1]
+	return s[tokens[0].pos : last.pos+len(last.text)]
+}
+
+func skipTo(s string, i int, end string) int {
+	n := strings.Index(s[i:], end)
+	if n < 0 {
+		return len(s)
+	}
+	return i + n + len(end)
+}
+
+// quotedEnd returns end offset of quoted text that starts at i. Doubled quote is treated as escaped quote.
+// It returns length of s and false when closing quote is not found.
+func quotedEnd(s string, i int, q byte) (int, bool) {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != q {
+			continue
+		}
+		if q != ']' && j+1 < len(s) && s[j+1] == q {
+			j++
+			continue
+		}
+		return j + 1, true
+	}
+	return len(s), false
+}
+
+// dollarTag returns tag of dollar quoted string like '$$' or '$body$'.
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		if s[j] == '$' {
+			return s[:j+1]
+		}
+		if !isIdentStart(rune(s[j])) {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
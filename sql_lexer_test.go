@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+func TestLexSQL(t *testing.T) {
+	tokens := lexSQL(`CREATE TABLE "my ""table"" " (id int DEFAULT 'it''s', -- comment
+  price numeric(10, 2) /* block */ CHECK (price >= 1.5e3))`)
+	expected := []string{"CREATE", "TABLE", `"my ""table"" "`, "(", "id", "int", "DEFAULT", `'it''s'`, ",", "price", "numeric", "(", "10", ",", "2", ")", "CHECK", "(", "price", ">=", "1.5e3", ")", ")"}
+	if len(tokens) != len(expected) {
+		t.Errorf("Invalid token count. expected: %v, actual: %v", len(expected), len(tokens))
+		return
+	}
+	for i, e := range expected {
+		if a := tokens[i].text; a != e {
+			t.Errorf("Invalid token at %d. expected: %v, actual: %v", i, e, a)
+		}
+	}
+	if a, e := tokens[2].ident(), `my "table" `; a != e {
+		t.Errorf("Quoted identifier should be unquoted. expected: %v, actual: %v", e, a)
+	}
+	if a, e := tokens[7].value(), "it's"; a != e {
+		t.Errorf("String literal should be unquoted. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestLexSQLDollarQuote(t *testing.T) {
+	tokens := lexSQL("SELECT $body$ it's; $x$ $body$;")
+	if len(tokens) != 3 {
+		t.Errorf("Dollar quoted string should be one token. actual: %v", len(tokens))
+		return
+	}
+	if a, e := tokens[1].value(), " it's; $x$ "; a != e {
+		t.Errorf("Dollar quoted string should be unquoted. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestLexSQLUnterminatedQuote(t *testing.T) {
+	tests := []struct {
+		sql   string
+		ident string
+		value string
+	}{
+		{`CREATE TABLE "`, "", `"`},
+		{`CREATE TABLE "foo`, "foo", `"foo`},
+		{`CREATE TABLE [foo`, "foo", "[foo"},
+		{`COMMENT ON TABLE foo IS '`, "'", ""},
+		{`COMMENT ON TABLE foo IS E'it''s`, "E'it''s", "it's"},
+		{`SELECT $$`, "$$", ""},
+		{`SELECT $body$ x`, "$body$ x", " x"},
+	}
+	for _, test := range tests {
+		tokens := lexSQL(test.sql)
+		last := tokens[len(tokens)-1]
+		if !last.unterminated {
+			t.Errorf("Last token of %q should be unterminated.", test.sql)
+		}
+		if a := last.ident(); a != test.ident {
+			t.Errorf("Invalid identifier of %q. expected: %v, actual: %v", test.sql, test.ident, a)
+		}
+		if a := last.value(); a != test.value {
+			t.Errorf("Invalid value of %q. expected: %v, actual: %v", test.sql, test.value, a)
+		}
+	}
+	if tokens := lexSQL(`SELECT "a", 'b', $$c$$`); tokens[1].unterminated || tokens[3].unterminated || tokens[5].unterminated {
+		t.Error("Closed quoted tokens should not be unterminated.")
+	}
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	stmts := splitSQLStatements(lexSQL("CREATE TABLE a (id int);; COMMENT ON TABLE a IS 'x;y'; SELECT 1"))
+	if len(stmts) != 3 {
+		t.Errorf("Invalid statement count. expected: %v, actual: %v", 3, len(stmts))
+	}
+}
+
+func TestMatchParen(t *testing.T) {
+	s := "CHECK ((a > 0) AND (b > 0)) x"
+	tokens := lexSQL(s)
+	end := matchParen(tokens, 1)
+	if a, e := sourceText(s, tokens[1:end+1]), "((a > 0) AND (b > 0))"; a != e {
+		t.Errorf("Invalid source text. expected: %v, actual: %v", e, a)
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const sqliteDefaultSchema = "main"
+
+// sqliteSource loads table definitions from sqlite_master and PRAGMA of SQLite database file.
+// host, port, user and password in config are ignored.
+type sqliteSource struct {
+	db *sql.DB
+}
+
+func newSqliteSource(config *Config) (*sqliteSource, error) {
+	path, err := resolvePath(config.Database)
+	if err != nil {
+		return nil, err
+	}
+	// sql.Open creates new empty database when file does not exist.
+	if _, err = os.Stat(path); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteSource{db: db}, nil
+}
+
+func (s *sqliteSource) AllTableNames(schema string) ([]*dbmodel.Table, error) {
+	tables, _, err := s.loadTables(sqliteSchemaName(schema))
+	return tables, err
+}
+
+func (s *sqliteSource) AllTables(schema string) ([]*dbmodel.Table, error) {
+	return s.load(sqliteSchemaName(schema), true)
+}
+
+func (s *sqliteSource) Table(schema string, name string, all bool) (*dbmodel.Table, error) {
+	tables, err := s.load(sqliteSchemaName(schema), all)
+	if err != nil {
+		return nil, err
+	}
+	for _, tbl := range tables {
+		if tbl.Name() == name {
+			return tbl, nil
+		}
+	}
+	return nil, fmt.Errorf("Table '%s' is not found.", name)
+}
+
+func (s *sqliteSource) Close() {
+	s.db.Close()
+}
+
+func sqliteSchemaName(schema string) string {
+	if schema == "" {
+		return sqliteDefaultSchema
+	}
+	return schema
+}
+
+// load loads all tables, because referenced keys of a table are known only by reading foreign keys of other tables.
+func (s *sqliteSource) load(schema string, all bool) ([]*dbmodel.Table, error) {
+	tables, ddls, err := s.loadTables(schema)
+	if err != nil {
+		return nil, err
+	}
+	colMap := make(map[string]*dbmodel.Column)
+	for _, tbl := range tables {
+		if err = s.loadColumns(schema, tbl, sqliteWithoutRowid(ddls[tbl.Name()]), colMap); err != nil {
+			return nil, err
+		}
+	}
+	if !all {
+		return tables, nil
+	}
+
+	tblMap := make(map[string]*dbmodel.Table)
+	for _, tbl := range tables {
+		tblMap[tbl.Name()] = tbl
+	}
+	for _, tbl := range tables {
+		if err = s.loadIndices(schema, tbl, colMap); err != nil {
+			return nil, err
+		}
+		loadSqliteCheckConstraints(schema, tbl, ddls[tbl.Name()])
+		if err = s.loadForeignKeys(schema, tbl, tblMap, colMap); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+func (s *sqliteSource) loadTables(schema string) ([]*dbmodel.Table, map[string]string, error) {
+	rows, err := s.db.Query(`
+SELECT name, sql
+FROM sqlite_master
+WHERE type = 'table'
+  AND name NOT LIKE 'sqlite_%'
+ORDER BY name`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tables := make([]*dbmodel.Table, 0)
+	ddls := make(map[string]string)
+	for rows.Next() {
+		var name string
+		var ddl sql.NullString
+		if err = rows.Scan(&name, &ddl); err != nil {
+			return nil, nil, err
+		}
+		tbl := dbmodel.NewTable(schema, name, "")
+		tables = append(tables, &tbl)
+		ddls[name] = ddl.String
+	}
+	return tables, ddls, rows.Err()
+}
+
+// loadColumns loads columns by PRAGMA table_info.
+// Column of primary key is nullable in SQLite unless it is INTEGER PRIMARY KEY (alias of rowid) or table is WITHOUT ROWID.
+func (s *sqliteSource) loadColumns(schema string, tbl *dbmodel.Table, withoutRowid bool, colMap map[string]*dbmodel.Column) error {
+	rows, err := s.db.Query(`SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, tbl.Name())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type columnInfo struct {
+		name, colType string
+		notNull, pk   int64
+		defVal        sql.NullString
+	}
+	infos := make([]columnInfo, 0)
+	pkCount := 0
+	for rows.Next() {
+		var info columnInfo
+		if err = rows.Scan(&info.name, &info.colType, &info.notNull, &info.defVal, &info.pk); err != nil {
+			return err
+		}
+		infos = append(infos, info)
+		if info.pk > 0 {
+			pkCount++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	for _, info := range infos {
+		dataType, size := parseDeclaredType(info.colType)
+		rowidAlias := pkCount == 1 && strings.EqualFold(dataType, "INTEGER")
+		nullable := info.notNull == 0 && !(info.pk > 0 && (withoutRowid || rowidAlias))
+		col := dbmodel.NewColumn(schema, tbl.Name(), info.name, "", dataType, size, nullable, info.defVal.String, info.pk)
+		tbl.AddColumn(&col)
+		colMap[columnKey(schema, tbl.Name(), info.name)] = &col
+	}
+	return nil
+}
+
+// sqliteWithoutRowid reports whether CREATE TABLE statement has WITHOUT ROWID option.
+func sqliteWithoutRowid(ddl string) bool {
+	tokens := lexSQL(ddl)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].is("WITHOUT") && tokens[i+1].is("ROWID") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sqliteSource) loadIndices(schema string, tbl *dbmodel.Table, colMap map[string]*dbmodel.Column) error {
+	rows, err := s.db.Query(`SELECT name, "unique" FROM pragma_index_list(?) ORDER BY name`, tbl.Name())
+	if err != nil {
+		return err
+	}
+	idxs := make([]*dbmodel.Index, 0)
+	for rows.Next() {
+		var name string
+		var unique int64
+		if err = rows.Scan(&name, &unique); err != nil {
+			rows.Close()
+			return err
+		}
+		idx := dbmodel.NewIndex(schema, tbl.Name(), name, unique == 1)
+		idxs = append(idxs, &idx)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, idx := range idxs {
+		cRows, err := s.db.Query(`SELECT name FROM pragma_index_info(?) ORDER BY seqno`, idx.Name())
+		if err != nil {
+			return err
+		}
+		for cRows.Next() {
+			var name sql.NullString
+			if err = cRows.Scan(&name); err != nil {
+				cRows.Close()
+				return err
+			}
+			// expression in index has no column name
+			if col, ok := colMap[columnKey(schema, tbl.Name(), name.String)]; ok {
+				idx.AddColumn(col)
+			}
+		}
+		cRows.Close()
+		if err = cRows.Err(); err != nil {
+			return err
+		}
+		tbl.AddIndex(idx)
+	}
+	return nil
+}
+
+func (s *sqliteSource) loadForeignKeys(schema string, tbl *dbmodel.Table, tblMap map[string]*dbmodel.Table, colMap map[string]*dbmodel.Column) error {
+	rows, err := s.db.Query(`SELECT id, "table", "from", "to" FROM pragma_foreign_key_list(?) ORDER BY id, seq`, tbl.Name())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type fkRow struct {
+		from string
+		to   sql.NullString
+	}
+	ids := make([]int64, 0)
+	parents := make(map[int64]string)
+	refs := make(map[int64][]fkRow)
+	for rows.Next() {
+		var id int64
+		var parent string
+		var r fkRow
+		if err = rows.Scan(&id, &parent, &r.from, &r.to); err != nil {
+			return err
+		}
+		if _, ok := parents[id]; !ok {
+			ids = append(ids, id)
+			parents[id] = parent
+		}
+		refs[id] = append(refs[id], r)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		parent := parents[id]
+		fromCols := make([]string, 0, len(refs[id]))
+		for _, r := range refs[id] {
+			fromCols = append(fromCols, r.from)
+		}
+		// SQLite does not keep foreign key name, so name is made like PostgreSQL default.
+		fk := dbmodel.NewForeignKey(schema, tbl.Name(), tbl.Name()+"_"+strings.Join(fromCols, "_")+"_fkey")
+		pkCols := sqlitePrimaryKeyColumns(tblMap[parent])
+		for i, r := range refs[id] {
+			to := r.to.String
+			// referenced columns are omitted when foreign key refers primary key
+			if !r.to.Valid && i < len(pkCols) {
+				to = pkCols[i]
+			}
+			ref := dbmodel.NewColumnReference(findOrStubColumn(colMap, schema, tbl.Name(), r.from), findOrStubColumn(colMap, schema, parent, to))
+			fk.AddColumnReference(&ref)
+		}
+		tbl.AddForeignKey(&fk)
+		if pTbl, ok := tblMap[parent]; ok {
+			pTbl.AddReferencedKey(&fk)
+		}
+	}
+	return nil
+}
+
+func sqlitePrimaryKeyColumns(tbl *dbmodel.Table) []string {
+	if tbl == nil {
+		return nil
+	}
+	names := make([]string, 0)
+	for pos := int64(1); ; pos++ {
+		found := false
+		for _, col := range tbl.Columns() {
+			if col.PrimaryKeyPosition() == pos {
+				names = append(names, col.Name())
+				found = true
+			}
+		}
+		if !found {
+			return names
+		}
+	}
+}
+
+// loadSqliteCheckConstraints reads CHECK constraints from CREATE TABLE statement, because PRAGMA does not provide them.
+func loadSqliteCheckConstraints(schema string, tbl *dbmodel.Table, ddl string) {
+	tokens := lexSQL(ddl)
+	n := 0
+	for i, t := range tokens {
+		if !t.is("CHECK") || i+1 >= len(tokens) || tokens[i+1].text != "(" {
+			continue
+		}
+		end := matchParen(tokens, i+1)
+		var name string
+		if i >= 2 && tokens[i-2].is("CONSTRAINT") {
+			name = tokens[i-1].ident()
+		} else {
+			name = tbl.Name() + "_check"
+			if n > 0 {
+				name += strconv.Itoa(n)
+			}
+			n++
+		}
+		con := dbmodel.NewConstraint(schema, tbl.Name(), name, "CHECK", sourceText(ddl, tokens[i+1:end+1]))
+		tbl.AddConstraint(&con)
+	}
+}
+
+// parseDeclaredType splits declared type like 'VARCHAR(255)' or 'DECIMAL(10, 2)' to type name and size.
+func parseDeclaredType(declared string) (string, dbmodel.Size) {
+	length := sql.NullInt64{}
+	precision := sql.NullInt64{}
+	scale := sql.NullInt64{}
+	i := strings.Index(declared, "(")
+	j := strings.LastIndex(declared, ")")
+	if i < 0 || j < i {
+		return strings.TrimSpace(declared), dbmodel.NewSize(length, precision, scale)
+	}
+
+	ps := strings.Split(declared[i+1:j], ",")
+	nums := make([]int64, 0, len(ps))
+	for _, p := range ps {
+		n, err := strconv.ParseInt(strings.TrimSpace(p), 10, 64)
+		if err != nil {
+			return strings.TrimSpace(declared), dbmodel.NewSize(length, precision, scale)
+		}
+		nums = append(nums, n)
+	}
+	if len(nums) == 1 {
+		length = sql.NullInt64{Int64: nums[0], Valid: true}
+	} else if len(nums) == 2 {
+		precision = sql.NullInt64{Int64: nums[0], Valid: true}
+		scale = sql.NullInt64{Int64: nums[1], Valid: true}
+	}
+	dataType := strings.TrimSpace(declared[:i] + declared[j+1:])
+	return dataType, dbmodel.NewSize(length, precision, scale)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdIndexWithSqlite(t *testing.T) {
+	if err := createSqliteTestDB(); err != nil {
+		t.Error(err)
+		return
+	}
+	buf := &bytes.Buffer{}
+	o.out = buf
+	setupTestConfigFile("tablarian-sqlite")
+	idxOpt.withoutTableComment = false
+	stat := cmdIndex.Run([]string{})
+	if stat != 0 {
+		t.Error("Index subcommand should finish normally.")
+	}
+	expected := `
+customers
+order_items
+orders
+products
+regions
+shipping_methods`
+	if actual := buf.String(); strings.TrimSpace(expected) != strings.TrimSpace(actual) {
+		t.Errorf("\nactual:\n%v\nexpected:%v\n", actual, expected)
+	}
+}
+
+func TestCmdShowWithSqlite(t *testing.T) {
+	if err := createSqliteTestDB(); err != nil {
+		t.Error(err)
+		return
+	}
+	initShowOpt()
+	buf := &bytes.Buffer{}
+	o.out = buf
+	setupTestConfigFile("tablarian-sqlite")
+	showOpt.showAll = true
+	stat := cmdShow.Run([]string{"orders"})
+	if stat != 0 {
+		t.Error("Show subcommand should finish normally.")
+	}
+	expected := strings.TrimSpace(`
++----+-------------+-----------+------+------+---------+---------+
+| PK |    NAME     |   TYPE    | SIZE | NULL | DEFAULT | COMMENT |
++----+-------------+-----------+------+------+---------+---------+
+|  1 | order_id    | INTEGER   |      | NO   |         |         |
+|    | customer_id | INTEGER   |      | NO   |         |         |
+|    | ordered_at  | TIMESTAMP |      | NO   |         |         |
+|    | status      | INTEGER   |      | NO   |       1 |         |
++----+-------------+-----------+------+------+---------+---------+
+
+### Indices
++------------------------+-------------+--------+
+|          NAME          |   COLUMNS   | UNIQUE |
++------------------------+-------------+--------+
+| idx_orders_customer_id | customer_id |        |
++------------------------+-------------+--------+
+
+### Constraints
++--------------+-------+-----------------------+
+|     NAME     | KIND  |        CONTENT        |
++--------------+-------+-----------------------+
+| orders_check | CHECK | (status IN (1, 2, 3)) |
++--------------+-------+-----------------------+
+
+### Foreign keys
++-------------------------+-------------+---------------+-----------------+
+|          NAME           |   COLUMNS   | FOREIGN TABLE | FOREIGN COLUMNS |
++-------------------------+-------------+---------------+-----------------+
+| orders_customer_id_fkey | customer_id | customers     | customer_id     |
++-------------------------+-------------+---------------+-----------------+
+
+### Referenced keys
++---------------------------+--------------+----------------+----------+
+|           NAME            | SOURCE TABLE | SOURCE COLUMNS | COLUMNS  |
++---------------------------+--------------+----------------+----------+
+| order_items_order_id_fkey | order_items  | order_id       | order_id |
++---------------------------+--------------+----------------+----------+`)
+	if actual := strings.TrimSpace(buf.String()); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdShowWithSqliteUnknownTable(t *testing.T) {
+	if err := createSqliteTestDB(); err != nil {
+		t.Error(err)
+		return
+	}
+	initShowOpt()
+	buf := &bytes.Buffer{}
+	o.err = buf
+	setupTestConfigFile("tablarian-sqlite")
+	stat := cmdShow.Run([]string{"foo"})
+	if stat == 0 {
+		t.Error("Show command should not finish normally on unknown table.")
+	}
+	if actual, expected := strings.TrimSpace(buf.String()), "Table 'foo' is not found."; actual != expected {
+		t.Errorf("Error masseage is not expected. actual: %v, expected: %v", actual, expected)
+	}
+}
+
+func TestSqliteForeignKeyToPrimaryKey(t *testing.T) {
+	if err := createSqliteTestDB(); err != nil {
+		t.Error(err)
+		return
+	}
+	src, err := newSqliteSource(&Config{Driver: "sqlite3", Database: sqliteTestDBPath})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer src.Close()
+
+	tbl, err := src.Table("", "customers", true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(tbl.ReferencedKeys()) != 1 {
+		t.Errorf("customers should be referenced by orders. actual: %v", len(tbl.ReferencedKeys()))
+		return
+	}
+	data := defaultConverter{}.ConvertReferencedKey(tbl.ReferencedKeys()[0])
+	if a, e := strings.Join(data, " | "), "orders_customer_id_fkey | orders | customer_id | customer_id"; a != e {
+		t.Errorf("Referenced column should be primary key when it is omitted. expected: %v, actual: %v", e, a)
+	}
+}
+
+func TestSqlitePrimaryKeyNullable(t *testing.T) {
+	if err := createSqliteTestDB(); err != nil {
+		t.Fatal(err)
+	}
+	src, err := newSqliteSource(&Config{Driver: "sqlite3", Database: sqliteTestDBPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	tests := []struct {
+		table    string
+		nullable bool
+	}{
+		{"customers", false},
+		{"order_items", false},
+		{"shipping_methods", true},
+		{"regions", false},
+	}
+	for _, tt := range tests {
+		tbl, err := src.Table("", tt.table, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if col := tbl.Columns()[0]; col.IsNullable() != tt.nullable {
+			t.Errorf("Nullable of %s.%s is invalid. expected: %v, actual: %v", tt.table, col.Name(), tt.nullable, col.IsNullable())
+		}
+	}
+}
+
+func TestSqliteWithoutDatabaseFile(t *testing.T) {
+	_, err := newSqliteSource(&Config{Driver: "sqlite3", Database: "test/not_found.sqlite3"})
+	if err == nil {
+		t.Error("Source should not be created when database file does not exist.")
+	}
+	if _, err = os.Stat(filepath.Join("test", "not_found.sqlite3")); err == nil {
+		t.Error("Database file should not be created.")
+	}
+}
+
+func TestParseDeclaredType(t *testing.T) {
+	tests := []struct {
+		declared string
+		dataType string
+		size     string
+	}{
+		{"INTEGER", "INTEGER", ""},
+		{"VARCHAR(255)", "VARCHAR", "255"},
+		{"DECIMAL(10, 2)", "DECIMAL", "10, 2"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		dataType, size := parseDeclaredType(tt.declared)
+		if dataType != tt.dataType {
+			t.Errorf("Invalid data type of %q. expected: %v, actual: %v", tt.declared, tt.dataType, dataType)
+		}
+		if size.String() != tt.size {
+			t.Errorf("Invalid size of %q. expected: %v, actual: %v", tt.declared, tt.size, size.String())
+		}
+	}
+}
+
+const sqliteTestDBPath = "test/tablarian_test.sqlite3"
+
+func createSqliteTestDB() error {
+	path, err := resolvePath(sqliteTestDBPath)
+	if err != nil {
+		return err
+	}
+	os.Remove(path)
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	b, err := readSQLFile("create_sqlite_resources")
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(string(b))
+	return err
+}
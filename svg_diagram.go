@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const (
+	svgCharWidth    = 7.5
+	svgFontSize     = 12
+	svgHeaderHeight = 24
+	svgRowHeight    = 18
+	svgPadding      = 8
+	svgColumnGap    = 96
+	svgBoxGap       = 24
+	svgMargin       = 20
+	svgChannelGap   = 8
+	svgMaxBoxes     = 8
+	svgSortSweeps   = 4
+)
+
+// svgBox is a table box in diagram.
+type svgBox struct {
+	tbl    *dbmodel.Table
+	layer  int
+	column int
+	x      float64
+	y      float64
+	w      float64
+	h      float64
+	rows   []string
+}
+
+// svgEdge is orthogonal line from column of foreign key to referenced column.
+type svgEdge struct {
+	rel    diagramRelation
+	points [][2]float64
+}
+
+// svgLayout is positions of boxes and edges.
+type svgLayout struct {
+	boxes  []*svgBox
+	edges  []*svgEdge
+	width  float64
+	height float64
+}
+
+// layoutSVG places tables in layers. Referenced tables are placed left of referencing tables,
+// tables without relations are placed in last layer, and layer that has many tables is wrapped into columns.
+// Order of tables in layer is decided by barycenter of related tables for reducing crossings.
+func layoutSVG(tables []*dbmodel.Table, conv Converter) *svgLayout {
+	rels := diagramRelations(tables)
+	boxes := make([]*svgBox, 0, len(tables))
+	boxMap := make(map[*dbmodel.Table]*svgBox)
+	for _, tbl := range tables {
+		b := newSVGBox(tbl, conv)
+		boxes = append(boxes, b)
+		boxMap[tbl] = b
+	}
+
+	parents := make(map[*svgBox][]*svgBox)
+	related := make(map[*svgBox][]*svgBox)
+	for _, rel := range rels {
+		if rel.parent == rel.child {
+			continue
+		}
+		c, p := boxMap[rel.child], boxMap[rel.parent]
+		parents[c] = append(parents[c], p)
+		related[c] = append(related[c], p)
+		related[p] = append(related[p], c)
+	}
+	assignSVGLayers(boxes, parents, related)
+	layers := sortSVGLayers(boxes, related)
+
+	l := &svgLayout{boxes: boxes}
+	x := float64(svgMargin)
+	column := 0
+	for _, layer := range layers {
+		for i := 0; i < len(layer); i += svgMaxBoxes {
+			end := i + svgMaxBoxes
+			if end > len(layer) {
+				end = len(layer)
+			}
+			y := float64(svgMargin)
+			width := 0.0
+			for _, b := range layer[i:end] {
+				b.column = column
+				b.x = x
+				b.y = y
+				y += b.h + svgBoxGap
+				if b.w > width {
+					width = b.w
+				}
+			}
+			if y-svgBoxGap+svgMargin > l.height {
+				l.height = y - svgBoxGap + svgMargin
+			}
+			x += width + svgColumnGap
+			column++
+		}
+	}
+	l.width = x - svgColumnGap + svgMargin
+	l.routeEdges(rels, boxMap)
+	return l
+}
+
+func newSVGBox(tbl *dbmodel.Table, conv Converter) *svgBox {
+	b := &svgBox{tbl: tbl}
+	maxLen := utf8.RuneCountInString(tbl.Name())
+	for _, col := range tbl.Columns() {
+		key := "  "
+		if col.PrimaryKeyPosition() > 0 {
+			key = "PK"
+		} else if isForeignKeyColumn(tbl, col) {
+			key = "FK"
+		}
+		row := fmt.Sprintf("%s %s : %s", key, col.Name(), diagramColumnType(conv, col))
+		b.rows = append(b.rows, row)
+		if n := utf8.RuneCountInString(row); n > maxLen {
+			maxLen = n
+		}
+	}
+	b.w = float64(maxLen)*svgCharWidth + svgPadding*2
+	b.h = float64(svgHeaderHeight + svgRowHeight*len(b.rows) + svgPadding/2)
+	return b
+}
+
+// assignSVGLayers sets layer that is next of deepest parent. Cycles are ignored.
+func assignSVGLayers(boxes []*svgBox, parents map[*svgBox][]*svgBox, related map[*svgBox][]*svgBox) {
+	done := make(map[*svgBox]bool)
+	visiting := make(map[*svgBox]bool)
+	var visit func(b *svgBox) int
+	visit = func(b *svgBox) int {
+		if done[b] || visiting[b] {
+			return b.layer
+		}
+		visiting[b] = true
+		for _, p := range parents[b] {
+			if l := visit(p) + 1; l > b.layer {
+				b.layer = l
+			}
+		}
+		visiting[b] = false
+		done[b] = true
+		return b.layer
+	}
+	maxLayer := 0
+	for _, b := range boxes {
+		if l := visit(b); l > maxLayer {
+			maxLayer = l
+		}
+	}
+	for _, b := range boxes {
+		if len(related[b]) == 0 && len(related) > 0 {
+			b.layer = maxLayer + 1
+		}
+	}
+}
+
+// sortSVGLayers groups boxes by layer and sorts boxes in each layer by barycenter of related boxes.
+func sortSVGLayers(boxes []*svgBox, related map[*svgBox][]*svgBox) [][]*svgBox {
+	layers := make([][]*svgBox, 0)
+	for _, b := range boxes {
+		for len(layers) <= b.layer {
+			layers = append(layers, make([]*svgBox, 0))
+		}
+		layers[b.layer] = append(layers[b.layer], b)
+	}
+	pos := make(map[*svgBox]float64)
+	for _, layer := range layers {
+		for i, b := range layer {
+			pos[b] = float64(i)
+		}
+	}
+	sortLayer := func(layer []*svgBox, useLower bool) {
+		keys := make(map[*svgBox]float64)
+		for _, b := range layer {
+			sum, n := 0.0, 0
+			for _, r := range related[b] {
+				if (useLower && r.layer < b.layer) || (!useLower && r.layer > b.layer) {
+					sum += pos[r]
+					n++
+				}
+			}
+			keys[b] = pos[b]
+			if n > 0 {
+				keys[b] = sum / float64(n)
+			}
+		}
+		sort.SliceStable(layer, func(i, j int) bool { return keys[layer[i]] < keys[layer[j]] })
+		for i, b := range layer {
+			pos[b] = float64(i)
+		}
+	}
+	for s := 0; s < svgSortSweeps; s++ {
+		for i := 1; i < len(layers); i++ {
+			sortLayer(layers[i], true)
+		}
+		for i := len(layers) - 2; i >= 0; i-- {
+			sortLayer(layers[i], false)
+		}
+	}
+	return layers
+}
+
+// routeEdges makes orthogonal lines that do not cross boxes.
+// Vertical segment of each line uses its own channel in gap between columns,
+// and line between distant columns goes across columns between them through gap between boxes.
+func (l *svgLayout) routeEdges(rels []diagramRelation, boxMap map[*dbmodel.Table]*svgBox) {
+	channels := make(map[string]int)
+	channel := func(key string) float64 {
+		n := channels[key]
+		channels[key] = n + 1
+		return float64(svgChannelGap * (n%(svgColumnGap/svgChannelGap-1) + 1))
+	}
+	right := make(map[int]float64)
+	for _, b := range l.boxes {
+		if b.x+b.w > right[b.column] {
+			right[b.column] = b.x + b.w
+		}
+	}
+	for _, rel := range rels {
+		refs := rel.fk.ColumnReferences()
+		c, p := boxMap[rel.child], boxMap[rel.parent]
+		cy := c.rowY(refs[0].From().Name())
+		py := p.rowY(refs[0].To().Name())
+		var points [][2]float64
+		switch {
+		case c.column == p.column+1:
+			cx := c.x - channel(fmt.Sprintf("L%d", c.column))
+			points = [][2]float64{{c.x, cy}, {cx, cy}, {cx, py}, {p.x + p.w, py}}
+		case c.column > p.column:
+			cx := c.x - channel(fmt.Sprintf("L%d", c.column))
+			px := right[p.column] + channel(fmt.Sprintf("R%d", p.column))
+			ly := l.laneY(p.column, c.column, py)
+			points = [][2]float64{{c.x, cy}, {cx, cy}, {cx, ly}, {px, ly}, {px, py}, {p.x + p.w, py}}
+		case c.column+1 == p.column:
+			cx := right[c.column] + channel(fmt.Sprintf("R%d", c.column))
+			points = [][2]float64{{c.x + c.w, cy}, {cx, cy}, {cx, py}, {p.x, py}}
+		case c.column < p.column:
+			cx := right[c.column] + channel(fmt.Sprintf("R%d", c.column))
+			px := p.x - channel(fmt.Sprintf("L%d", p.column))
+			ly := l.laneY(c.column, p.column, py)
+			points = [][2]float64{{c.x + c.w, cy}, {cx, cy}, {cx, ly}, {px, ly}, {px, py}, {p.x, py}}
+		default:
+			cx := right[c.column] + channel(fmt.Sprintf("R%d", c.column))
+			points = [][2]float64{{c.x + c.w, cy}, {cx, cy}, {cx, py}, {p.x + p.w, py}}
+		}
+		l.edges = append(l.edges, &svgEdge{rel: rel, points: points})
+	}
+	if len(l.edges) > 0 && len(l.boxes) > 0 {
+		// Lines of last column may go out of right side.
+		l.width += svgColumnGap / 2
+	}
+}
+
+// laneY returns y nearest to given y where horizontal line crosses columns between left and right without crossing boxes.
+// Margin above boxes is always free.
+func (l *svgLayout) laneY(left int, right int, y float64) float64 {
+	between := make([]*svgBox, 0)
+	candidates := []float64{y, svgMargin / 2}
+	for _, b := range l.boxes {
+		if left < b.column && b.column < right {
+			between = append(between, b)
+			candidates = append(candidates, b.y-svgBoxGap/2, b.y+b.h+svgBoxGap/2)
+		}
+	}
+	lane := float64(svgMargin / 2)
+	for _, cand := range candidates {
+		free := true
+		for _, b := range between {
+			if b.y <= cand && cand <= b.y+b.h {
+				free = false
+				break
+			}
+		}
+		if free && math.Abs(cand-y) < math.Abs(lane-y) {
+			lane = cand
+		}
+	}
+	return lane
+}
+
+// rowY returns center y of row of column, or center of header when column is not found.
+func (b *svgBox) rowY(name string) float64 {
+	for i, col := range b.tbl.Columns() {
+		if col.Name() == name {
+			return b.y + svgHeaderHeight + svgRowHeight*float64(i) + svgRowHeight/2
+		}
+	}
+	return b.y + svgHeaderHeight/2
+}
+
+// renderSVG renders ER diagram. link returns URL of table, and table is not linked when it returns empty string.
+func renderSVG(tables []*dbmodel.Table, conv Converter, link func(*dbmodel.Table) string) []byte {
+	l := layoutSVG(tables, conv)
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%g" height="%g" viewBox="0 0 %g %g" font-family="monospace" font-size="%d">`+"\n",
+		l.width, l.height, l.width, l.height, svgFontSize)
+	fmt.Fprintln(buf, `<defs>`)
+	fmt.Fprintln(buf, `<marker id="one" viewBox="0 0 12 12" refX="12" refY="6" markerWidth="12" markerHeight="12" orient="auto"><path d="M 6 1 L 6 11" stroke="#555" fill="none"/></marker>`)
+	fmt.Fprintln(buf, `<marker id="zero-or-one" viewBox="0 0 16 12" refX="16" refY="6" markerWidth="16" markerHeight="12" orient="auto"><path d="M 11 1 L 11 11" stroke="#555" fill="none"/><circle cx="5" cy="6" r="3" stroke="#555" fill="#fff"/></marker>`)
+	fmt.Fprintln(buf, `<marker id="many" viewBox="0 0 12 12" refX="0" refY="6" markerWidth="12" markerHeight="12" orient="auto"><path d="M 12 6 L 0 1 M 12 6 L 0 11 M 12 6 L 0 6" stroke="#555" fill="none"/></marker>`)
+	fmt.Fprintln(buf, `</defs>`)
+	fmt.Fprintf(buf, `<rect width="%g" height="%g" fill="#fff"/>`+"\n", l.width, l.height)
+
+	// Lines are drawn under boxes, and they are routed not to cross boxes.
+	for _, e := range l.edges {
+		pts := ""
+		for i, pt := range e.points {
+			if i > 0 {
+				pts += " "
+			}
+			pts += fmt.Sprintf("%g,%g", pt[0], pt[1])
+		}
+		end := "one"
+		if e.rel.parentOptional {
+			end = "zero-or-one"
+		}
+		start := ""
+		if e.rel.childMany {
+			start = ` marker-start="url(#many)"`
+		}
+		fmt.Fprintf(buf, `<polyline class="relation" points="%s" fill="none" stroke="#555"%s marker-end="url(#%s)"><title>%s</title></polyline>`+"\n",
+			pts, start, end, xmlEscape(e.rel.fk.Name()))
+	}
+
+	for _, b := range l.boxes {
+		fmt.Fprintf(buf, `<g class="table" id="%s">`+"\n", xmlEscape("table-"+b.tbl.Name()))
+		if b.tbl.Comment() != "" {
+			fmt.Fprintf(buf, `<title>%s</title>`+"\n", xmlEscape(b.tbl.Comment()))
+		}
+		fmt.Fprintf(buf, `<rect x="%g" y="%g" width="%g" height="%g" fill="#fff" stroke="#333"/>`+"\n", b.x, b.y, b.w, b.h)
+		fmt.Fprintf(buf, `<rect x="%g" y="%g" width="%g" height="%d" fill="#34495e" stroke="#333"/>`+"\n", b.x, b.y, b.w, svgHeaderHeight)
+		name := xmlEscape(b.tbl.Name())
+		if url := link(b.tbl); url != "" {
+			name = fmt.Sprintf(`<a xlink:href="%s" href="%s" fill="#fff">%s</a>`, xmlEscape(url), xmlEscape(url), name)
+		}
+		fmt.Fprintf(buf, `<text x="%g" y="%g" fill="#fff" font-weight="bold">%s</text>`+"\n", b.x+svgPadding, b.y+svgHeaderHeight-8, name)
+		for i, row := range b.rows {
+			fmt.Fprintf(buf, `<text x="%g" y="%g" fill="#333" xml:space="preserve">%s</text>`+"\n",
+				b.x+svgPadding, b.y+svgHeaderHeight+svgRowHeight*float64(i+1)-5, xmlEscape(row))
+		}
+		fmt.Fprintln(buf, `</g>`)
+	}
+	fmt.Fprintln(buf, `</svg>`)
+	return buf.Bytes()
+}
+
+// writeSVGDiagrams writes er.svg and neighborhood diagram of each table as er/TABLE.svg when svg of diagram is enabled.
+// It returns tables that have diagram. link returns URL of table from output directory.
+func (p *basePublisher) writeSVGDiagrams(tables []*dbmodel.Table, link func(*dbmodel.Table) string) ([]*dbmodel.Table, error) {
+	if !p.cfg.Diagram.SVG {
+		return nil, nil
+	}
+	tbls, err := diagramTables(tables, p.cfg.Diagram)
+	if err != nil {
+		return nil, err
+	}
+	p.write("er.svg", renderSVG(tbls, p.conv, link))
+	for _, tbl := range tbls {
+		neighbors, _ := diagramTables(tbls, DiagramConfig{Focus: tbl.Name(), Hops: 1})
+		p.write(svgTableDiagramPath(tbl), renderSVG(neighbors, p.conv, func(t *dbmodel.Table) string {
+			if url := link(t); url != "" {
+				return "../" + url
+			}
+			return ""
+		}))
+	}
+	return tbls, nil
+}
+
+func svgTableDiagramPath(tbl *dbmodel.Table) string {
+	return "er/" + tbl.Name() + ".svg"
+}
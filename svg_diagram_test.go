@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+func loadAdventureWorksTables(t *testing.T) []*dbmodel.Table {
+	b, err := ioutil.ReadFile(filepath.Join("test", "create_postgres_resources.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tablesFromDDL(string(b))
+}
+
+func TestLayoutSVG(t *testing.T) {
+	tables := loadAdventureWorksTables(t)
+	l := layoutSVG(tables, findConverter(true, "postgres"))
+	if expected, actual := len(tables), len(l.boxes); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if len(l.edges) == 0 {
+		t.Error("Layout should have edges.")
+	}
+
+	for i, a := range l.boxes {
+		if a.x < 0 || a.y < 0 || a.x+a.w > l.width || a.y+a.h > l.height {
+			t.Errorf("%s is out of diagram.", a.tbl.Name())
+		}
+		for _, b := range l.boxes[i+1:] {
+			if a.x < b.x+b.w && b.x < a.x+a.w && a.y < b.y+b.h && b.y < a.y+a.h {
+				t.Errorf("%s overlaps %s.", a.tbl.Name(), b.tbl.Name())
+			}
+		}
+	}
+
+	for _, e := range l.edges {
+		for i := 1; i < len(e.points); i++ {
+			p, q := e.points[i-1], e.points[i]
+			if p[0] != q[0] && p[1] != q[1] {
+				t.Errorf("Segment of %s is not orthogonal: %v -> %v", e.rel.fk.Name(), p, q)
+			}
+		}
+		if e.points[len(e.points)-1][0] > l.width {
+			t.Errorf("Line of %s is out of diagram.", e.rel.fk.Name())
+		}
+	}
+}
+
+func TestLayoutSVGEdgesDoNotCrossBoxes(t *testing.T) {
+	l := layoutSVG(loadAdventureWorksTables(t), findConverter(true, "postgres"))
+	for _, e := range l.edges {
+		for i := 1; i < len(e.points); i++ {
+			p, q := e.points[i-1], e.points[i]
+			minX, maxX := math.Min(p[0], q[0]), math.Max(p[0], q[0])
+			minY, maxY := math.Min(p[1], q[1]), math.Max(p[1], q[1])
+			for _, b := range l.boxes {
+				if minX < b.x+b.w && b.x < maxX && minY < b.y+b.h && b.y < maxY {
+					t.Errorf("Line of %s crosses %s: %v -> %v", e.rel.fk.Name(), b.tbl.Name(), p, q)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutSVGPlacesParentLeft(t *testing.T) {
+	l := layoutSVG(tablesFromDDL(diagramTestDDL), findConverter(true, "postgres"))
+	columns := make(map[string]int)
+	for _, b := range l.boxes {
+		columns[b.tbl.Name()] = b.column
+	}
+	tests := []struct {
+		parent string
+		child  string
+	}{
+		{"shops", "staffs"},
+		{"staffs", "staff_profiles"},
+		{"shops", "orders"},
+		{"orders", "order_items"},
+	}
+	for _, test := range tests {
+		if columns[test.parent] >= columns[test.child] {
+			t.Errorf("%s should be placed left of %s. columns: %v", test.parent, test.child, columns)
+		}
+	}
+	for name, col := range columns {
+		if name != "settings" && col >= columns["settings"] {
+			t.Errorf("Table without relations should be placed in last column. columns: %v", columns)
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL)
+	svg := renderSVG(tables, findConverter(true, "postgres"), func(tbl *dbmodel.Table) string {
+		if tbl.Name() == "settings" {
+			return ""
+		}
+		return tbl.Name() + ".html"
+	})
+
+	dec := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG should be well-formed XML: %v", err)
+		}
+	}
+
+	s := string(svg)
+	for _, tbl := range tables {
+		if !strings.Contains(s, `<g class="table" id="table-`+tbl.Name()+`">`) {
+			t.Errorf("SVG should have box of %s", tbl.Name())
+		}
+	}
+	for _, expected := range []string{
+		`<a xlink:href="shops.html" href="shops.html" fill="#fff">shops</a>`,
+		`font-weight="bold">settings</text>`,
+		`>PK id : serial</text>`,
+		`>FK shop_id : integer</text>`,
+		`marker-start="url(#many)" marker-end="url(#zero-or-one)"><title>staffs_shop_id_fkey</title>`,
+		`marker-end="url(#one)"><title>staff_profiles_staff_id_fkey</title>`,
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("SVG should contain %s", expected)
+		}
+	}
+	if expected, actual := 4, strings.Count(s, `<polyline class="relation"`); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCmdPublishMarkdownWithSVG(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	publishOpt.svg = true
+	publishOpt.verbose = true
+	buf := &bytes.Buffer{}
+	o.out = buf
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/er.svg
+Created: out/er/posts.svg
+Created: out/er/users.svg
+Created: out/posts.md
+Created: out/users.md
+Created: out/00_index.md
+`
+	if actual := strings.Replace(buf.String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	if s, idx := "\n## ER diagram\n\n![ER diagram](er.svg)\n", readPublished(t, dir, "00_index.md"); !strings.HasSuffix(idx, s) {
+		t.Errorf("00_index.md should end with %s, actual:\n%s", s, idx)
+	}
+	if s, md := "\n## ER diagram\n\n![ER diagram](er/posts.svg)\n", readPublished(t, dir, "posts.md"); !strings.HasSuffix(md, s) {
+		t.Errorf("posts.md should end with %s, actual:\n%s", s, md)
+	}
+	if s, svg := `xlink:href="../users.md"`, readPublished(t, dir, "er/posts.svg"); !strings.Contains(svg, s) {
+		t.Errorf("er/posts.svg should contain %s", s)
+	}
+}
+
+func TestCmdPublishHTMLWithSVG(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "html"
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	publishOpt.svg = true
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	if s, idx := `<p class="diagram"><a href="er.svg"><img src="er.svg" alt="ER diagram"></a></p>`, readPublished(t, dir, "index.html"); !strings.Contains(idx, s) {
+		t.Errorf("index.html should contain %s", s)
+	}
+	if s, page := `<img src="../er/users.svg" alt="ER diagram">`, readPublished(t, dir, "tables/users.html"); !strings.Contains(page, s) {
+		t.Errorf("tables/users.html should contain %s", s)
+	}
+	if s, svg := `xlink:href="tables/posts.html"`, readPublished(t, dir, "er.svg"); !strings.Contains(svg, s) {
+		t.Errorf("er.svg should contain %s", s)
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// TemplateConfig is setting of template format.
+// Table and Index are paths of text/template files for table page and index page, and Ext is extension of pages.
+// Built-in markdown templates are used when they are not set.
+type TemplateConfig struct {
+	Table string `json:"table"`
+	Index string `json:"index"`
+	Ext   string `json:"ext"`
+}
+
+// templateData is data that templates are executed against.
+// Table is nil in index template, and Tables has all published tables in both templates.
+type templateData struct {
+	Table  *templateTable
+	Tables []*templateTable
+}
+
+// templateTable has fields of table document that is described by tablarian.schema.json (fields are named in Go style),
+// and Rows has values that converter makes for each element.
+type templateTable struct {
+	*documentTable
+	Rows templateRows
+}
+
+// templateRows are rows of sections in markdown.
+type templateRows struct {
+	Columns        [][]string
+	Indices        [][]string
+	Constraints    [][]string
+	ForeignKeys    [][]string
+	ReferencedKeys [][]string
+}
+
+// templateMarkdownTable renders markdown table in same layout as markdown format.
+// Methods return empty string because they are called in templates.
+type templateMarkdownTable struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *templateMarkdownTable) Append(row []string) string {
+	t.rows = append(t.rows, row)
+	return ""
+}
+
+func (t *templateMarkdownTable) Render() string {
+	buf := &bytes.Buffer{}
+	w := newMdTableWriter(buf)
+	w.SetHeader(t.headers)
+	w.AppendBulk(t.rows)
+	w.Render()
+	return buf.String()
+}
+
+const defaultTableTemplate = `# {{.Table.Name}}
+{{with .Table.Comment}}
+{{.}}
+{{end}}
+## {{t "column" "title"}}
+
+{{$w := markdownTable (headers "column" "primary_key" "name" "data_type" "size" "null" "default_value" "comment")}}
+{{- range .Table.Rows.Columns}}{{$w.Append .}}{{end}}
+{{- $w.Render}}
+{{- if .Table.Indices}}
+## {{t "index" "title"}}
+
+{{$w := markdownTable (headers "index" "name" "columns" "unique")}}
+{{- range .Table.Rows.Indices}}{{$w.Append .}}{{end}}
+{{- $w.Render}}
+{{- end}}
+{{- if .Table.Constraints}}
+## {{t "constraint" "title"}}
+
+{{$w := markdownTable (headers "constraint" "name" "kind" "content")}}
+{{- range .Table.Rows.Constraints}}{{$w.Append .}}{{end}}
+{{- $w.Render}}
+{{- end}}
+{{- if .Table.ForeignKeys}}
+## {{t "foreign_key" "title"}}
+
+{{$w := markdownTable (headers "foreign_key" "name" "columns" "foreign_table" "foreign_columns")}}
+{{- range .Table.Rows.ForeignKeys}}{{$w.Append .}}{{end}}
+{{- $w.Render}}
+{{- end}}
+{{- if .Table.ReferencedKeys}}
+## {{t "referenced_key" "title"}}
+
+{{$w := markdownTable (headers "referenced_key" "name" "source_table" "source_columns" "columns")}}
+{{- range .Table.Rows.ReferencedKeys}}{{$w.Append .}}{{end}}
+{{- $w.Render}}
+{{- end}}`
+
+const defaultIndexTemplate = `# {{t "table_list" "title"}}
+
+{{$w := markdownTable (headers "table_list" "table" "comment")}}
+{{- range .Tables}}{{$w.Append (row (printf "[%s](%s)" .Name (page .Name)) .Comment)}}{{end}}
+{{- $w.Render}}`
+
+// templatePublisher writes pages by user templates.
+type templatePublisher struct {
+	basePublisher
+}
+
+func newTemplatePublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *templatePublisher {
+	return &templatePublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *templatePublisher) Publish(tables []*dbmodel.Table) {
+	ext := p.cfg.Templates.Ext
+	if ext == "" {
+		ext = ".md"
+	}
+	tblTmpl, err := loadPageTemplate("table", p.cfg.Templates.Table, defaultTableTemplate, p.loc, ext)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	idxTmpl, err := loadPageTemplate("index", p.cfg.Templates.Index, defaultIndexTemplate, p.loc, ext)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	if err = p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	data := &templateData{Tables: newTemplateTables(tables, p.conv)}
+	for _, tbl := range data.Tables {
+		b, err := executeTemplate(tblTmpl, &templateData{Table: tbl, Tables: data.Tables})
+		if err != nil {
+			p.errors = append(p.errors, err)
+			return
+		}
+		p.write(tbl.Name+ext, b)
+	}
+	b, err := executeTemplate(idxTmpl, data)
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write("00_index"+ext, b)
+}
+
+// loadPageTemplate parses template file of path, or text when path is empty.
+func loadPageTemplate(name string, path string, text string, loc locale, ext string) (*template.Template, error) {
+	if path != "" {
+		rpath, err := resolvePath(path)
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadFile(rpath)
+		if err != nil {
+			return nil, err
+		}
+		name, text = filepath.Base(rpath), string(b)
+	}
+	return template.New(name).Funcs(templateFuncs(loc, ext)).Parse(text)
+}
+
+func executeTemplate(tmpl *template.Template, data *templateData) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// templateFuncs returns helper functions for templates.
+//
+//	t CATEGORY KEY            translation of locale
+//	headers CATEGORY KEYS...  translations of KEYS in CATEGORY
+//	row VALUES...             row for Append of markdown table
+//	markdownTable HEADERS     markdown table that has Append and Render
+//	page NAME                 file name of table page
+//	anchor TEXT               anchor of markdown heading
+//	join LIST SEP             strings.Join
+//	escape TEXT               escapes text for markdown table cell
+func templateFuncs(loc locale, ext string) template.FuncMap {
+	return template.FuncMap{
+		"t": loc.t,
+		"headers": func(cat string, keys ...string) []string {
+			return translateHeaders(loc, cat, keys...)
+		},
+		"row": func(values ...string) []string {
+			return values
+		},
+		"markdownTable": func(headers []string) *templateMarkdownTable {
+			return &templateMarkdownTable{headers: headers}
+		},
+		"page": func(name string) string {
+			return name + ext
+		},
+		"anchor": markdownAnchor,
+		"join":   strings.Join,
+		"escape": markdownEscape,
+	}
+}
+
+func newTemplateTables(tables []*dbmodel.Table, conv Converter) []*templateTable {
+	tts := make([]*templateTable, 0, len(tables))
+	for _, tbl := range tables {
+		tt := &templateTable{documentTable: newDocumentTable(tbl, conv)}
+		for _, col := range tbl.Columns() {
+			tt.Rows.Columns = append(tt.Rows.Columns, conv.ConvertColumn(col))
+		}
+		for _, idx := range tbl.Indices() {
+			tt.Rows.Indices = append(tt.Rows.Indices, conv.ConvertIndex(idx))
+		}
+		for _, con := range tbl.Constraints() {
+			tt.Rows.Constraints = append(tt.Rows.Constraints, conv.ConvertConstraint(con))
+		}
+		for _, fk := range tbl.ForeignKeys() {
+			tt.Rows.ForeignKeys = append(tt.Rows.ForeignKeys, conv.ConvertForeignKey(fk))
+		}
+		for _, rk := range tbl.ReferencedKeys() {
+			tt.Rows.ReferencedKeys = append(tt.Rows.ReferencedKeys, conv.ConvertReferencedKey(rk))
+		}
+		tts = append(tts, tt)
+	}
+	return tts
+}
+
+// markdownEscape escapes pipe in table cell, and line break is written as br.
+func markdownEscape(text string) string {
+	text = strings.Replace(text, "|", `\|`, -1)
+	return strings.Replace(text, "\n", "<br>", -1)
+}
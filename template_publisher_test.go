@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDefaultTemplatesAreSameAsMarkdown(t *testing.T) {
+	tables := tablesFromDDL(diagramTestDDL + `
+COMMENT ON TABLE shops IS 'Shops';
+ALTER TABLE shops ADD CONSTRAINT shops_name_check CHECK (name <> '');
+`)
+	for _, lang := range []string{"en", "ja"} {
+		for _, pretty := range []bool{true, false} {
+			conv, loc := findConverter(pretty, "postgres"), l(lang)
+			tblTmpl, err := loadPageTemplate("table", "", defaultTableTemplate, loc, ".md")
+			if err != nil {
+				t.Fatal(err)
+			}
+			idxTmpl, err := loadPageTemplate("index", "", defaultIndexTemplate, loc, ".md")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tts := newTemplateTables(tables, conv)
+			for i, tt := range tts {
+				actual, err := executeTemplate(tblTmpl, &templateData{Table: tt, Tables: tts})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if expected := convertToMarkdown(tables[i], conv, loc); !bytes.Equal(expected, actual) {
+					t.Errorf("\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+				}
+			}
+			actual, err := executeTemplate(idxTmpl, &templateData{Tables: tts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := convertToIndexMarkdown(tables, loc); !bytes.Equal(expected, actual) {
+				t.Errorf("\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+			}
+		}
+	}
+}
+
+func TestCmdPublishTemplate(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "template"
+	publishOpt.locale = "ja"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-template")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/posts.txt
+Created: out/users.txt
+Created: out/00_index.txt
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `posts: Posts of users
+- id int4 NOT NULL
+- user_id int4 NOT NULL
+- title text NOT NULL
+参照キー: user_id -> users.txt#users
+
+`
+	if actual := readPublished(t, dir, "posts.txt"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `posts.txt (3)
+users.txt (3)
+
+`
+	if actual := readPublished(t, dir, "00_index.txt"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdPublishTemplateWithDefaultTemplates(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "template"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/posts.md
+Created: out/users.md
+Created: out/00_index.md
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `# Table index
+
+|       TABLE       |    COMMENT     |
+|-------------------|----------------|
+| [posts](posts.md) | Posts of users |
+| [users](users.md) |                |
+`
+	if actual := readPublished(t, dir, "00_index.md"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestPublishTemplateWithInvalidTemplate(t *testing.T) {
+	if _, err := loadPageTemplate("table", "", "{{.Table.Name", l("en"), ".md"); err == nil {
+		t.Error("invalid template should be error")
+	}
+}
+
+func TestMarkdownAnchor(t *testing.T) {
+	if expected, actual := "order_items-テーブル", markdownAnchor("Order_items (テーブル)"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	if expected, actual := `a\|b<br>c`, markdownEscape("a|b\nc"); expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,42 @@
+CREATE TABLE customers (
+  customer_id INTEGER PRIMARY KEY,
+  name VARCHAR(50) NOT NULL,
+  email VARCHAR(255) NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE products (
+  product_id INTEGER PRIMARY KEY,
+  name VARCHAR(50) NOT NULL,
+  price DECIMAL(10, 2) NOT NULL DEFAULT 0,
+  CONSTRAINT ck_products_price CHECK (price >= 0)
+);
+
+CREATE TABLE orders (
+  order_id INTEGER PRIMARY KEY,
+  customer_id INTEGER NOT NULL REFERENCES customers,
+  ordered_at TIMESTAMP NOT NULL,
+  status INTEGER NOT NULL DEFAULT 1 CHECK (status IN (1, 2, 3))
+);
+
+CREATE INDEX idx_orders_customer_id ON orders (customer_id);
+
+CREATE TABLE order_items (
+  order_id INTEGER NOT NULL,
+  line_no INTEGER NOT NULL,
+  product_id INTEGER NOT NULL,
+  quantity INTEGER NOT NULL DEFAULT 1,
+  PRIMARY KEY (order_id, line_no),
+  FOREIGN KEY (order_id) REFERENCES orders (order_id),
+  FOREIGN KEY (product_id) REFERENCES products (product_id)
+);
+
+CREATE TABLE shipping_methods (
+  code TEXT PRIMARY KEY,
+  name VARCHAR(50) NOT NULL
+);
+
+CREATE TABLE regions (
+  code TEXT PRIMARY KEY,
+  name VARCHAR(50) NOT NULL
+) WITHOUT ROWID;
@@ -0,0 +1 @@
+-- not a migration
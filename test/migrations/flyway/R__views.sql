@@ -0,0 +1 @@
+CREATE VIEW user_posts AS SELECT * FROM posts;
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN email varchar(100);
+CREATE UNIQUE INDEX users_email_idx ON users (email);
@@ -0,0 +1,6 @@
+CREATE TABLE posts (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users (id),
+    title text NOT NULL
+);
+COMMENT ON TABLE posts IS 'Posts of users';
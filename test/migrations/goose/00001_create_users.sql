@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id serial PRIMARY KEY,
+    name varchar(50) NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE users;
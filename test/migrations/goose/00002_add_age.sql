@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN age integer CHECK (age >= 0);
+CREATE VIEW adults AS SELECT * FROM users WHERE age >= 20;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN age;
@@ -0,0 +1,4 @@
+CREATE TABLE users (
+    id serial PRIMARY KEY,
+    name varchar(50) NOT NULL
+);
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+// textilePublisher writes wiki pages in Textile for Redmine and Backlog.
+// Table pages are written in tables directory so that page of table named index does not overwrite index.textile.
+type textilePublisher struct {
+	basePublisher
+}
+
+func newTextilePublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *textilePublisher {
+	return &textilePublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *textilePublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	pages := make(map[string]string)
+	for _, tbl := range tables {
+		pages[ddlKey(tbl.Schema(), tbl.Name())] = tbl.Name()
+	}
+	for _, tbl := range tables {
+		p.write("tables/"+tbl.Name()+".textile", convertToTextile(tbl, pages, p.conv, p.loc))
+	}
+	p.write("index.textile", convertToIndexTextile(tables, p.loc))
+}
+
+// convertToTextile makes same sections as convertToMarkdown.
+// Tables of foreign keys and referenced keys are wiki links when they are in pages.
+func convertToTextile(table *dbmodel.Table, pages map[string]string, conv Converter, loc locale) []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "h1.", table.Name())
+	if table.Comment() != "" {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, table.Comment())
+	}
+
+	rows := make([][]string, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		rows = append(rows, textileCells(conv.ConvertColumn(col)))
+	}
+	writeTextileTable(buf, loc.t("column", "title"), translateHeaders(loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"), rows)
+
+	if len(table.Indices()) > 0 {
+		rows = make([][]string, 0, len(table.Indices()))
+		for _, idx := range table.Indices() {
+			rows = append(rows, textileCells(conv.ConvertIndex(idx)))
+		}
+		writeTextileTable(buf, loc.t("index", "title"), translateHeaders(loc, "index", "name", "columns", "unique"), rows)
+	}
+
+	if len(table.Constraints()) > 0 {
+		rows = make([][]string, 0, len(table.Constraints()))
+		for _, con := range table.Constraints() {
+			rows = append(rows, textileCells(conv.ConvertConstraint(con)))
+		}
+		writeTextileTable(buf, loc.t("constraint", "title"), translateHeaders(loc, "constraint", "name", "kind", "content"), rows)
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ForeignKeys()))
+		for _, fk := range table.ForeignKeys() {
+			row := textileCells(conv.ConvertForeignKey(fk))
+			if refs := fk.ColumnReferences(); len(refs) > 0 {
+				if page, ok := pages[ddlKey(refs[0].To().Schema(), refs[0].To().TableName())]; ok {
+					row[2] = textileLink(page)
+				}
+			}
+			rows = append(rows, row)
+		}
+		writeTextileTable(buf, loc.t("foreign_key", "title"), translateHeaders(loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"), rows)
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		rows = make([][]string, 0, len(table.ReferencedKeys()))
+		for _, rk := range table.ReferencedKeys() {
+			row := textileCells(conv.ConvertReferencedKey(rk))
+			if page, ok := pages[ddlKey(rk.Schema(), rk.TableName())]; ok {
+				row[1] = textileLink(page)
+			}
+			rows = append(rows, row)
+		}
+		writeTextileTable(buf, loc.t("referenced_key", "title"), translateHeaders(loc, "referenced_key", "name", "source_table", "source_columns", "columns"), rows)
+	}
+
+	return buf.Bytes()
+}
+
+// convertToIndexTextile makes index page that has wiki links to all table pages.
+func convertToIndexTextile(tables []*dbmodel.Table, loc locale) []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "h1.", loc.t("table_list", "title"))
+	fmt.Fprintln(buf)
+	rows := make([][]string, 0, len(tables))
+	for _, tbl := range tables {
+		rows = append(rows, []string{textileLink(tbl.Name()), textileCell(tbl.Comment())})
+	}
+	writeTextileRows(buf, translateHeaders(loc, "table_list", "table", "comment"), rows)
+	return buf.Bytes()
+}
+
+func writeTextileTable(buf *bytes.Buffer, title string, headers []string, rows [][]string) {
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "h2.", title)
+	fmt.Fprintln(buf)
+	writeTextileRows(buf, headers, rows)
+}
+
+// writeTextileRows writes table that has header row. Values of rows must be escaped.
+func writeTextileRows(buf *bytes.Buffer, headers []string, rows [][]string) {
+	for _, h := range headers {
+		fmt.Fprintf(buf, "|_. %s ", textileCell(h))
+	}
+	fmt.Fprintln(buf, "|")
+	for _, row := range rows {
+		for _, v := range row {
+			fmt.Fprintf(buf, "|%s ", v)
+		}
+		fmt.Fprintln(buf, "|")
+	}
+}
+
+func textileLink(page string) string {
+	return "[[" + page + "]]"
+}
+
+func textileCells(values []string) []string {
+	cells := make([]string, 0, len(values))
+	for _, v := range values {
+		cells = append(cells, textileCell(v))
+	}
+	return cells
+}
+
+// textileCell escapes cell separator. Line break is replaced with space because row must be in a line.
+func textileCell(s string) string {
+	s = strings.Replace(s, "|", "&#124;", -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
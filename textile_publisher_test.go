@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvertToTextile(t *testing.T) {
+	tables := tablesFromDDL(`
+CREATE TABLE shops (id serial PRIMARY KEY, name varchar(50) NOT NULL DEFAULT 'a|b');
+COMMENT ON TABLE shops IS 'Shops';
+COMMENT ON COLUMN shops.name IS 'Name
+of shop';
+CREATE TABLE staffs (id serial PRIMARY KEY, shop_id int NOT NULL REFERENCES shops (id));
+`)
+	var shops = tables[0]
+	if shops.Name() != "shops" {
+		shops = tables[1]
+	}
+	pages := map[string]string{ddlKey("public", "shops"): "shops", ddlKey("public", "staffs"): "staffs"}
+
+	expected := `h1. shops
+
+Shops
+
+h2. Columns
+
+|_. PK |_. NAME |_. TYPE |_. SIZE |_. NULL |_. DEFAULT |_. COMMENT |
+|1 |id |serial | |NO | | |
+| |name |varchar |50 |NO |'a&#124;b' |Name of shop |
+
+h2. Indices
+
+|_. NAME |_. COLUMNS |_. UNIQUE |
+|shops_pkey |id |YES |
+
+h2. Referenced keys
+
+|_. NAME |_. SOURCE TABLE |_. SOURCE COLUMNS |_. COLUMNS |
+|staffs_shop_id_fkey |[[staffs]] |shop_id |id |
+`
+	if actual := string(convertToTextile(shops, pages, findConverter(true, "postgres"), l("en"))); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+}
+
+func TestCmdPublishTextile(t *testing.T) {
+	if err := initPublishMarkdownTest(); err != nil {
+		t.Fatal("Failure test initialization.")
+	}
+	publishOpt.format = "textile"
+	publishOpt.locale = "ja"
+	publishOpt.verbose = true
+	publishOpt.migrationsDir = "test/migrations/flyway"
+	o.out = &bytes.Buffer{}
+	o.err = &bytes.Buffer{}
+	setupTestConfigFile("tablarian-migration")
+	if stat := cmdPublish.Run([]string{}); stat != 0 {
+		t.Fatal("Publish subcommand should finish normally.")
+	}
+	dir, _ := resolvePath("out")
+	expected := `Created: out/tables/posts.textile
+Created: out/tables/users.textile
+Created: out/index.textile
+`
+	if actual := strings.Replace(o.out.(*bytes.Buffer).String(), dir, "out", -1); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `h1. テーブル一覧
+
+|_. テーブル |_. コメント |
+|[[posts]] |Posts of users |
+|[[users]] | |
+`
+	if actual := readPublished(t, dir, "index.textile"); expected != actual {
+		t.Errorf("\nactual:\n%v\nexpected:\n%v\n", actual, expected)
+	}
+	expected = `h2. 参照キー
+
+|_. 参照名 |_. 列 |_. 参照テーブル |_. 参照列 |
+|posts_user_id_fkey |user_id |[[users]] |id |
+`
+	if actual := readPublished(t, dir, "tables/posts.textile"); !strings.HasSuffix(actual, expected) {
+		t.Errorf("tables/posts.textile should end with %s, actual:\n%s", expected, actual)
+	}
+}
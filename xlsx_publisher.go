@@ -0,0 +1,338 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pinzolo/dbmodel"
+)
+
+const (
+	xlsxFileName         = "tables.xlsx"
+	xlsxMaxSheetName     = 31
+	xlsxMaxColumnWidth   = 60
+	xlsxStyleHeader      = 1
+	xlsxStyleHyperlink   = 2
+	xlsxSheetNameIllegal = `[]:*?/\`
+)
+
+// xlsxPublisher writes a workbook that has index sheet and sheet per table.
+// Workbook is written with standard library only, so Office is not required.
+type xlsxPublisher struct {
+	basePublisher
+}
+
+type xlsxSheet struct {
+	name  string
+	rows  [][]xlsxCell
+	links []xlsxLink
+}
+
+type xlsxCell struct {
+	value string
+	style int
+}
+
+// xlsxLink is hyperlink to cell A1 of other sheet.
+type xlsxLink struct {
+	ref   string
+	sheet string
+}
+
+func newXlsxPublisher(config *Config, converter Converter, locale locale, out publishOutput, logger io.Writer) *xlsxPublisher {
+	return &xlsxPublisher{newBasePublisher(config, converter, locale, out, logger)}
+}
+
+func (p *xlsxPublisher) Publish(tables []*dbmodel.Table) {
+	if err := p.out.Prepare(); err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+
+	idx := &xlsxSheet{name: xlsxSheetName(p.loc.t("table_list", "title"), nil)}
+	used := []string{idx.name}
+	sheets := make([]*xlsxSheet, 0, len(tables))
+	for _, tbl := range tables {
+		sheet := p.tableSheet(tbl)
+		sheet.name = xlsxSheetName(tbl.Name(), used)
+		used = append(used, sheet.name)
+		sheets = append(sheets, sheet)
+	}
+
+	idx.addRow(xlsxStyleHeader, p.loc.t("table_list", "title"))
+	idx.addRow(0)
+	idx.addRow(xlsxStyleHeader, translateHeaders(p.loc, "table_list", "table", "comment")...)
+	for i, tbl := range tables {
+		idx.addRow(0, tbl.Name(), tbl.Comment())
+		idx.rows[len(idx.rows)-1][0].style = xlsxStyleHyperlink
+		idx.links = append(idx.links, xlsxLink{ref: xlsxCellRef(0, len(idx.rows)-1), sheet: sheets[i].name})
+	}
+
+	b, err := buildXlsx(append([]*xlsxSheet{idx}, sheets...))
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return
+	}
+	p.write(xlsxFileName, b)
+}
+
+// tableSheet makes sheet that has same sections as table markdown.
+func (p *xlsxPublisher) tableSheet(table *dbmodel.Table) *xlsxSheet {
+	sheet := &xlsxSheet{}
+	sheet.addRow(xlsxStyleHeader, table.Name())
+	if table.Comment() != "" {
+		sheet.addRow(0, table.Comment())
+	}
+
+	sheet.addSection(p.loc.t("column", "title"), translateHeaders(p.loc, "column", "primary_key", "name", "data_type", "size", "null", "default_value", "comment"))
+	for _, col := range table.Columns() {
+		sheet.addRow(0, p.conv.ConvertColumn(col)...)
+	}
+
+	if len(table.Indices()) > 0 {
+		sheet.addSection(p.loc.t("index", "title"), translateHeaders(p.loc, "index", "name", "columns", "unique"))
+		for _, idx := range table.Indices() {
+			sheet.addRow(0, p.conv.ConvertIndex(idx)...)
+		}
+	}
+
+	if len(table.Constraints()) > 0 {
+		sheet.addSection(p.loc.t("constraint", "title"), translateHeaders(p.loc, "constraint", "name", "kind", "content"))
+		for _, con := range table.Constraints() {
+			sheet.addRow(0, p.conv.ConvertConstraint(con)...)
+		}
+	}
+
+	if len(table.ForeignKeys()) > 0 {
+		sheet.addSection(p.loc.t("foreign_key", "title"), translateHeaders(p.loc, "foreign_key", "name", "columns", "foreign_table", "foreign_columns"))
+		for _, fk := range table.ForeignKeys() {
+			sheet.addRow(0, p.conv.ConvertForeignKey(fk)...)
+		}
+	}
+
+	if len(table.ReferencedKeys()) > 0 {
+		sheet.addSection(p.loc.t("referenced_key", "title"), translateHeaders(p.loc, "referenced_key", "name", "source_table", "source_columns", "columns"))
+		for _, rk := range table.ReferencedKeys() {
+			sheet.addRow(0, p.conv.ConvertReferencedKey(rk)...)
+		}
+	}
+
+	return sheet
+}
+
+func (s *xlsxSheet) addRow(style int, values ...string) {
+	row := make([]xlsxCell, 0, len(values))
+	for _, v := range values {
+		row = append(row, xlsxCell{value: v, style: style})
+	}
+	s.rows = append(s.rows, row)
+}
+
+// addSection adds blank row, title row and header row.
+func (s *xlsxSheet) addSection(title string, headers []string) {
+	s.addRow(0)
+	s.addRow(xlsxStyleHeader, title)
+	s.addRow(xlsxStyleHeader, headers...)
+}
+
+// xlsxSheetName returns name that Excel accepts as sheet name and is not contained in used names.
+func xlsxSheetName(name string, used []string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(xlsxSheetNameIllegal, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "_"
+	}
+	candidate := truncateRunes(name, xlsxMaxSheetName)
+	for i := 2; containsFold(used, candidate); i++ {
+		suffix := fmt.Sprintf("(%d)", i)
+		candidate = truncateRunes(name, xlsxMaxSheetName-len(suffix)) + suffix
+	}
+	return candidate
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// containsFold returns true when ss contains s without case sensitivity like sheet names of Excel.
+func containsFold(ss []string, s string) bool {
+	for _, v := range ss {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// xlsxCellRef returns reference like 'A1' from zero based column and row.
+func xlsxCellRef(col int, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return fmt.Sprintf("%s%d", name, row+1)
+}
+
+// buildXlsx makes xlsx file from sheets. Cells are written as inline strings.
+func buildXlsx(sheets []*xlsxSheet) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes(len(sheets))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(sheets))},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, s := range sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), s.xml()})
+	}
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(w, f.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+const (
+	xlsxRootRels = ooxmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxStyles = ooxmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font>` +
+		`<font><u/><sz val="11"/><color rgb="FF0563C1"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`
+)
+
+func xlsxContentTypes(n int) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString(ooxmlHeader)
+	buf.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	buf.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	buf.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	buf.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	buf.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(buf, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	buf.WriteString(`</Types>`)
+	return buf.String()
+}
+
+func xlsxWorkbook(sheets []*xlsxSheet) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString(ooxmlHeader)
+	buf.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range sheets {
+		fmt.Fprintf(buf, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(s.name), i+1, i+1)
+	}
+	buf.WriteString(`</sheets></workbook>`)
+	return buf.String()
+}
+
+func xlsxWorkbookRels(n int) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString(ooxmlHeader)
+	buf.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(buf, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(buf, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, n+1)
+	buf.WriteString(`</Relationships>`)
+	return buf.String()
+}
+
+func (s *xlsxSheet) xml() string {
+	buf := &bytes.Buffer{}
+	buf.WriteString(ooxmlHeader)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	widths := make([]int, 0)
+	for _, row := range s.rows {
+		for i, c := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := utf8.RuneCountInString(c.value); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	if len(widths) > 0 {
+		buf.WriteString(`<cols>`)
+		for i, w := range widths {
+			if w > xlsxMaxColumnWidth {
+				w = xlsxMaxColumnWidth
+			}
+			fmt.Fprintf(buf, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, w+2)
+		}
+		buf.WriteString(`</cols>`)
+	}
+
+	buf.WriteString(`<sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(buf, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			if cell.value == "" {
+				continue
+			}
+			fmt.Fprintf(buf, `<c r="%s"`, xlsxCellRef(c, r))
+			if cell.style > 0 {
+				fmt.Fprintf(buf, ` s="%d"`, cell.style)
+			}
+			fmt.Fprintf(buf, ` t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, xmlEscape(cell.value))
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData>`)
+
+	if len(s.links) > 0 {
+		buf.WriteString(`<hyperlinks>`)
+		for _, l := range s.links {
+			location := "'" + strings.Replace(l.sheet, "'", "''", -1) + "'!A1"
+			fmt.Fprintf(buf, `<hyperlink ref="%s" location="%s" display="%s"/>`, l.ref, xmlEscape(location), xmlEscape(l.sheet))
+		}
+		buf.WriteString(`</hyperlinks>`)
+	}
+	buf.WriteString(`</worksheet>`)
+	return buf.String()
+}
+
+func xmlEscape(s string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
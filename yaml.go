@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var yamlPlainPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.()/, -]*$`)
+
+// yamlReservedWords are words that YAML parsers read as other than string.
+var yamlReservedWords = []string{"y", "n", "yes", "no", "on", "off", "true", "false", "null"}
+
+// yamlNode is a value of JSON. Keys of object are kept in order of JSON.
+type yamlNode struct {
+	kind   string
+	scalar string
+	keys   []string
+	values []*yamlNode
+}
+
+// convertJSONToYAML converts JSON to YAML in block style.
+// Order of keys is kept, so YAML has same order as JSON.
+func convertJSONToYAML(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	n, err := readYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if n.isCollection() {
+		writeYAMLNode(buf, n, 0)
+	} else {
+		fmt.Fprintln(buf, n.scalar)
+	}
+	return buf.Bytes(), nil
+}
+
+func readYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			n := &yamlNode{kind: "object"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := readYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+				n.values = append(n.values, value)
+			}
+			_, err = dec.Token()
+			return n, err
+		}
+		n := &yamlNode{kind: "array"}
+		for dec.More() {
+			value, err := readYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, value)
+		}
+		_, err = dec.Token()
+		return n, err
+	case string:
+		return &yamlNode{kind: "scalar", scalar: yamlString(v)}, nil
+	case json.Number:
+		return &yamlNode{kind: "scalar", scalar: v.String()}, nil
+	case bool:
+		return &yamlNode{kind: "scalar", scalar: fmt.Sprint(v)}, nil
+	}
+	return &yamlNode{kind: "scalar", scalar: "null"}, nil
+}
+
+// isCollection returns true when node is written as block. Empty object and empty array are written in flow style.
+func (n *yamlNode) isCollection() bool {
+	return n.kind != "scalar" && len(n.values) > 0
+}
+
+func (n *yamlNode) flow() string {
+	switch n.kind {
+	case "object":
+		return "{}"
+	case "array":
+		return "[]"
+	}
+	return n.scalar
+}
+
+func writeYAMLNode(buf *bytes.Buffer, n *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, v := range n.values {
+		if n.kind == "object" {
+			if v.isCollection() {
+				fmt.Fprintf(buf, "%s%s:\n", pad, yamlString(n.keys[i]))
+				writeYAMLNode(buf, v, indent+2)
+			} else {
+				fmt.Fprintf(buf, "%s%s: %s\n", pad, yamlString(n.keys[i]), v.flow())
+			}
+			continue
+		}
+		if !v.isCollection() {
+			fmt.Fprintf(buf, "%s- %s\n", pad, v.flow())
+			continue
+		}
+		// first line of item is written after '-'.
+		item := &bytes.Buffer{}
+		writeYAMLNode(item, v, indent+2)
+		buf.WriteString(pad + "- ")
+		buf.Write(item.Bytes()[indent+2:])
+	}
+}
+
+// yamlString returns plain string when it is safe, otherwise double quoted string.
+// Escapes of JSON string are valid in double quoted string of YAML.
+func yamlString(s string) string {
+	if yamlPlainPattern.MatchString(s) && !strings.HasSuffix(s, " ") && !containsString(yamlReservedWords, strings.ToLower(s)) {
+		return s
+	}
+	b, _ := json.Marshal(s)
+	return string(b)
+}